@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/erwanlbp/trading-bot/pkg/config"
+	"github.com/erwanlbp/trading-bot/pkg/service"
+)
+
+// Download Binance klines into the price and ratio history, marked as backfilled.
+//
+//	go run cmd/backfill/main.go [-interval 1m] -from 2024-06-01 [-to 2024-07-01] COIN1 [COIN2...]
+//
+// Ratios are derived for every pair of the coins, so the coins they're paired with are fetched too.
+// A run covers 90 days at most, backfill a longer history in several runs
+func main() {
+	interval := flag.String("interval", "1m", "Kline interval, 1m or 5m")
+	from := flag.String("from", "", "Start date (UTC), 2006-01-02 or 2006-01-02T15:04")
+	to := flag.String("to", "", "End date (UTC), same format, default is now")
+	flag.Parse()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
+	defer cancel()
+
+	start, err := parseDate(*from)
+	if err != nil {
+		fail("Invalid -from: " + err.Error())
+	}
+	end := time.Now().UTC()
+	if *to != "" {
+		if end, err = parseDate(*to); err != nil {
+			fail("Invalid -to: " + err.Error())
+		}
+	}
+
+	var coins []string
+	for _, arg := range flag.Args() {
+		coins = append(coins, strings.Split(strings.ToUpper(arg), ",")...)
+	}
+	req := service.BackfillRequest{Coins: coins, Interval: *interval, From: start, To: end}
+	if err := req.Validate(); err != nil {
+		fail(err.Error())
+	}
+
+	conf := config.Init(ctx)
+
+	// Backfilled rows need the columns of the latest schema, the bot may not have run since the update
+	if err := conf.DB.MigrateSchema(); err != nil {
+		fail("Failed to migrate DB schema: " + err.Error())
+	}
+
+	res, err := conf.Service.Backfill(ctx, req)
+	if err != nil {
+		fail(fmt.Sprintf("Backfill failed (%s): %s", res.Describe(), err.Error()))
+	}
+	fmt.Println(res.Describe())
+}
+
+func parseDate(s string) (time.Time, error) {
+	for _, layout := range []string{"2006-01-02T15:04", time.DateOnly} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("couldn't parse '%s', expected 2006-01-02 or 2006-01-02T15:04", s)
+}
+
+func fail(msg string) {
+	fmt.Println(msg)
+	os.Exit(1)
+}
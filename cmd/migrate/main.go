@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/erwanlbp/trading-bot/pkg/config"
+	"github.com/erwanlbp/trading-bot/pkg/config/configfile"
+	"github.com/erwanlbp/trading-bot/pkg/db"
+	"github.com/erwanlbp/trading-bot/pkg/log"
+)
+
+const usage = `Usage: migrate <command>
+
+  status          List the migrations and whether they're applied
+  up [version]    Apply the pending migrations, up to the version if given
+  down <version>  Revert the applied migrations above the version`
+
+// Show and apply the DB schema migrations, the bot applies the pending ones when starting
+func main() {
+	if len(os.Args) < 2 {
+		fail(usage)
+	}
+
+	cf, err := configfile.ParseConfigFile()
+	if err != nil {
+		fail("Failed to parse config file: " + err.Error())
+	}
+
+	database, err := config.OpenDB(log.NewSimpleZapLogger(), &cf)
+	if err != nil {
+		fail("Failed to open DB: " + err.Error())
+	}
+	migrator, err := database.SchemaMigrator()
+	if err != nil {
+		fail("Invalid migrations: " + err.Error())
+	}
+
+	switch os.Args[1] {
+	case "status":
+		printStatus(migrator)
+	case "up":
+		var target uint
+		if len(os.Args) > 2 {
+			target = parseVersion(os.Args[2])
+		}
+		applied, err := migrator.Up(target)
+		for _, m := range applied {
+			fmt.Printf("Applied %d %s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			fail(err.Error())
+		}
+		if len(applied) == 0 {
+			fmt.Println("Nothing to apply")
+		}
+	case "down":
+		if len(os.Args) < 3 {
+			fail(usage)
+		}
+		reverted, err := migrator.Down(parseVersion(os.Args[2]))
+		for _, m := range reverted {
+			fmt.Printf("Reverted %d %s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			fail(err.Error())
+		}
+		if len(reverted) == 0 {
+			fmt.Println("Nothing to revert")
+		}
+	default:
+		fail(usage)
+	}
+}
+
+func printStatus(migrator *db.Migrator) {
+	status, err := migrator.Status()
+	if err != nil {
+		fail("Failed to get migrations status: " + err.Error())
+	}
+
+	for _, s := range status {
+		state := "pending"
+		switch {
+		case s.Unknown:
+			state = "applied, unknown to this version of the bot"
+		case s.Detected:
+			state = "detected " + s.AppliedOn.Format(time.DateTime)
+		case s.Applied:
+			state = "applied " + s.AppliedOn.Format(time.DateTime)
+		}
+		fmt.Printf("%4d  %-30s %s\n", s.Version, s.Name, state)
+	}
+}
+
+func parseVersion(s string) uint {
+	version, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		fail(fmt.Sprintf("Invalid version '%s'", s))
+	}
+	return uint(version)
+}
+
+func fail(msg string) {
+	fmt.Println(msg)
+	os.Exit(1)
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/erwanlbp/trading-bot/pkg/config"
+	"github.com/erwanlbp/trading-bot/pkg/config/configfile"
+	"github.com/erwanlbp/trading-bot/pkg/eventbus"
+)
+
+// Feed recorded journal files back into a fresh event bus, printing the events.
+//
+//	go run cmd/replay/main.go [-speed 10] [-jump-finder [-db replay.db]] [files...]
+//
+// Without files, all the files of the configured journal dir are replayed.
+//
+// The jump finder never uses the configured DB, it runs on a scratch SQLite file: a temporary one removed at the end, or the -db one to look at it afterwards.
+// It doesn't use the telegram bot either: nothing is sent to the channel and proposals can't be approved.
+// Trading windows and blackouts are checked at the recorded time, but the pairs, liquidity and slippage still come from the live Binance data
+func main() {
+	speed := flag.Float64("speed", 1, "Replay speed, 1 is real time, 0 is as fast as possible")
+	withJumpFinder := flag.Bool("jump-finder", false, "Run the jump finder on the replayed prices, only allowed in test mode")
+	dbPath := flag.String("db", "", "SQLite file of the jump finder, a temporary one if empty. Never the configured DB")
+	flag.Parse()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
+	defer cancel()
+
+	files := flag.Args()
+	if len(files) == 0 {
+		cf, err := configfile.ParseConfigFile()
+		if err != nil {
+			fail("Failed to parse config file:", err)
+		}
+		files, err = eventbus.JournalFiles(cf.Journal.Path())
+		if err != nil {
+			fail("Failed to list journal files:", err)
+		}
+	}
+
+	var records []eventbus.JournalRecord
+	for _, file := range files {
+		f, err := os.Open(file)
+		if err != nil {
+			fail("Failed to open journal:", err)
+		}
+		fileRecords, err := eventbus.ReadJournal(f)
+		f.Close()
+		if err != nil {
+			fail(fmt.Sprintf("Failed to read journal %s:", file), err)
+		}
+		records = append(records, fileRecords...)
+	}
+	if len(records) == 0 {
+		fmt.Println("No event to replay")
+		return
+	}
+	fmt.Printf("Replaying %d events from %s to %s\n", len(records), records[0].Time.Format(time.DateTime), records[len(records)-1].Time.Format(time.DateTime))
+
+	bus := eventbus.NewEventBus()
+	if *withJumpFinder {
+		if *dbPath == "" {
+			dir, err := os.MkdirTemp("", "replay")
+			if err != nil {
+				fail("Failed to create temporary dir:", err)
+			}
+			defer os.RemoveAll(dir)
+			*dbPath = filepath.Join(dir, "replay.db")
+		}
+		fmt.Println("Jump finder DB is", *dbPath)
+		bus = startJumpFinder(ctx, *dbPath)
+	}
+
+	printer := bus.SubscribeAll(eventbus.SubscriptionOptions{BufferSize: 256, Policy: eventbus.Block})
+	go printer.Handler(ctx, func(_ context.Context, event eventbus.Event) {
+		fmt.Printf("%s %v\n", event.Name, event.Payload)
+	})
+
+	stats, err := eventbus.Replay(ctx, bus, records, *speed)
+	if err != nil {
+		fail("Replay stopped:", err)
+	}
+
+	// Let the subscribers handle what's left
+	for pending(bus) > 0 && ctx.Err() == nil {
+		time.Sleep(100 * time.Millisecond)
+	}
+	time.Sleep(time.Second)
+
+	fmt.Printf("Replayed %d events, skipped %d\n", stats.Replayed, stats.Skipped)
+}
+
+// Init the bot like the main on a scratch DB without telegram, but only start the jump finder. Test mode is required so it can't trade on the real account
+func startJumpFinder(ctx context.Context, dbPath string) *eventbus.Bus {
+	if err := os.Setenv(config.DBPathEnv, dbPath); err != nil {
+		fail("Failed to set the DB path:", err)
+	}
+	conf := config.InitOffline(ctx)
+	logger := conf.Logger
+
+	if !conf.ConfigFile.TestMode {
+		logger.Fatal("The jump finder can only be replayed with test_mode enabled")
+	}
+	if same, _ := sameFile(dbPath, config.DBFilePath(conf.ConfigFile)); same {
+		logger.Fatal("The jump finder can't be replayed on the bot's DB, use another -db file")
+	}
+
+	if err := conf.DB.MigrateSchema(); err != nil {
+		logger.Fatal("failed to migrate DB schema", zap.Error(err))
+	}
+	if err := config.LoadCoins(conf.ConfigFile.Coins, conf.ConfigFile.VirtualOnlyCoins(), logger, conf.Repository); err != nil {
+		logger.Fatal("failed to load supported coins", zap.Error(err))
+	}
+	if err := conf.Service.InitializePairs(ctx); err != nil {
+		logger.Fatal("failed initializing coin pairs", zap.Error(err))
+	}
+
+	conf.ProcessJumpFinder.Start(ctx)
+
+	return conf.EventBus
+}
+
+func sameFile(a, b string) (bool, error) {
+	absA, err := filepath.Abs(a)
+	if err != nil {
+		return false, err
+	}
+	absB, err := filepath.Abs(b)
+	if err != nil {
+		return false, err
+	}
+	return absA == absB, nil
+}
+
+func pending(bus *eventbus.Bus) int {
+	var res int
+	for _, stats := range bus.Stats() {
+		res += stats.Pending
+	}
+	return res
+}
+
+func fail(msg string, err error) {
+	fmt.Println(msg, err.Error())
+	os.Exit(1)
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/erwanlbp/trading-bot/pkg/config"
+	"github.com/erwanlbp/trading-bot/pkg/config/configfile"
+	"github.com/erwanlbp/trading-bot/pkg/db"
+	"github.com/erwanlbp/trading-bot/pkg/db/postgres"
+	"github.com/erwanlbp/trading-bot/pkg/db/sqlite"
+	"github.com/erwanlbp/trading-bot/pkg/log"
+	"github.com/erwanlbp/trading-bot/pkg/util"
+)
+
+// Replace the DB by a backup, the bot must be stopped.
+//
+//	go run cmd/restore/main.go              list the backups
+//	go run cmd/restore/main.go [-yes] FILE  restore the backup, a file of the backup dir or a path
+func main() {
+	yes := flag.Bool("yes", false, "Don't ask for confirmation")
+	flag.Parse()
+
+	cf, err := configfile.ParseConfigFile()
+	if err != nil {
+		fail("Failed to parse config file: " + err.Error())
+	}
+
+	if flag.NArg() == 0 {
+		listBackups(cf.Backup.Path())
+		return
+	}
+
+	backup := flag.Arg(0)
+	if _, err := os.Stat(backup); err != nil {
+		backup = filepath.Join(cf.Backup.Path(), flag.Arg(0))
+	}
+	if _, err := os.Stat(backup); err != nil {
+		fail("Backup not found: " + err.Error())
+	}
+
+	if pid, running := config.RunningBotPID(&cf); running {
+		fail(fmt.Sprintf("The bot is running (PID %d), stop it before restoring", pid))
+	}
+
+	target := config.DBFilePath(&cf)
+	if cf.Database.IsPostgres() {
+		target = "the postgres database"
+	}
+	if !*yes && !confirm(fmt.Sprintf("Replace %s by %s ?", target, backup)) {
+		fmt.Println("Canceled")
+		return
+	}
+
+	if cf.Database.IsPostgres() {
+		if err := postgres.Restore(context.Background(), cf.Database.DSN, backup); err != nil {
+			fail(err.Error())
+		}
+	} else {
+		if err := restoreSQLite(backup, config.DBFilePath(&cf)); err != nil {
+			fail(err.Error())
+		}
+	}
+
+	fmt.Println("Restored ✅ Pending migrations will be applied when the bot starts")
+}
+
+func listBackups(dir string) {
+	backups, err := db.ListBackups(dir)
+	if err != nil {
+		fail("Failed to list backups: " + err.Error())
+	}
+	if len(backups) == 0 {
+		fmt.Println("No backup in " + dir)
+		return
+	}
+	for _, b := range backups {
+		fmt.Printf("%-7s %s %10s  %s\n", b.Kind, b.Time.Format(time.DateTime), util.ByteCountSI(b.Size), filepath.Base(b.Path))
+	}
+}
+
+// Check the backup in a temporary file next to the DB, then swap them. The current DB is kept aside
+func restoreSQLite(backup, dbPath string) error {
+	tmp := dbPath + ".restore"
+	var err error
+	if strings.HasSuffix(backup, ".gz") {
+		err = db.GunzipFile(backup, tmp)
+	} else {
+		err = db.CopyFile(backup, tmp)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to copy the backup: %w", err)
+	}
+
+	if err := checkSQLite(tmp); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("invalid backup: %w", err)
+	}
+
+	if _, err := os.Stat(dbPath); err == nil {
+		aside := fmt.Sprintf("%s.before-restore-%s", dbPath, time.Now().UTC().Format("2006-01-02T150405"))
+		// Journal files belong to the current DB
+		for _, suffix := range []string{"", "-wal", "-shm", "-journal"} {
+			if _, err := os.Stat(dbPath + suffix); err != nil {
+				continue
+			}
+			if err := os.Rename(dbPath+suffix, aside+suffix); err != nil {
+				return fmt.Errorf("failed to move the current DB aside: %w", err)
+			}
+		}
+		fmt.Println("Current DB moved to " + aside)
+	}
+
+	if err := os.Rename(tmp, dbPath); err != nil {
+		return fmt.Errorf("failed to move the backup in place: %w", err)
+	}
+	return nil
+}
+
+// Integrity check, and the schema must not be newer than this version of the bot
+func checkSQLite(path string) error {
+	gormDB, err := sqlite.NewDB(log.NewSimpleZapLogger(), path)
+	if err != nil {
+		return err
+	}
+	sqlDB, err := gormDB.DB()
+	if err != nil {
+		return err
+	}
+	defer sqlDB.Close()
+
+	var integrity string
+	if err := gormDB.Raw("PRAGMA integrity_check").Scan(&integrity).Error; err != nil {
+		return err
+	}
+	if integrity != "ok" {
+		return fmt.Errorf("integrity check failed: %s", integrity)
+	}
+
+	migrator, err := db.NewDB(gormDB).SchemaMigrator()
+	if err != nil {
+		return err
+	}
+	status, err := migrator.Status()
+	if err != nil {
+		return err
+	}
+	for _, s := range status {
+		if s.Unknown {
+			return fmt.Errorf("it has migration %d '%s', unknown to this version of the bot", s.Version, s.Name)
+		}
+	}
+	return nil
+}
+
+func confirm(question string) bool {
+	fmt.Print(question + " [y/N] ")
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+func fail(msg string) {
+	fmt.Println(msg)
+	os.Exit(1)
+}
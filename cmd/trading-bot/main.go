@@ -34,19 +34,30 @@ func main() {
 
 	logger := conf.Logger
 
+	if err := config.WritePIDFile(conf.ConfigFile); err != nil {
+		logger.Warn("Failed to write the PID file, the restore command won't know the bot is running", zap.Error(err))
+	}
+	defer config.RemovePIDFile(conf.ConfigFile)
+
+	logger.Debug("Starting event journal process")
+	conf.ProcessEventJournal.Start(ctx)
+
 	logger.Debug("Starting Telegram notification process")
 	conf.ProcessTelegramNotifier.Start(ctx)
 
 	logger.Debug("Starting symbol blacklister process")
 	conf.ProcessSymbolBlacklister.Start(ctx)
 
+	logger.Debug("Starting binance refreshers")
+	conf.BinanceClient.StartRefreshers(ctx)
+
 	logger.Debug("Creating the DB if needed")
 	if err := conf.DB.MigrateSchema(); err != nil {
 		logger.Fatal("failed to migrate DB schema", zap.Error(err))
 	}
 
 	logger.Debug("Loading supported coins")
-	if err := config.LoadCoins(conf.ConfigFile.Coins, logger, conf.Repository); err != nil {
+	if err := config.LoadCoins(conf.ConfigFile.Coins, conf.ConfigFile.VirtualOnlyCoins(), logger, conf.Repository); err != nil {
 		logger.Fatal("failed to load supported coins", zap.Error(err))
 	}
 
@@ -57,6 +68,7 @@ func main() {
 
 	logger.Debug("Init telegram handlers")
 	conf.TelegramHandlers.InitHandlers(ctx)
+	conf.ProcessJumpFinder.InitTelegramHandlers(ctx)
 
 	logger.Debug("Starting telegram bot")
 	conf.TelegramClient.StartBot()
@@ -66,13 +78,28 @@ func main() {
 		conf.ProcessFeeGetter.Start(ctx)
 	}
 
+	logger.Debug("Starting price validator process")
+	conf.ProcessPriceValidator.Start(ctx)
+
 	if ok, _ := strconv.ParseBool(os.Getenv("NO_JUMP")); !ok {
 		logger.Debug("Starting jump finder process")
 		conf.ProcessJumpFinder.Start(ctx)
+
+		logger.Debug("Starting take profiter process")
+		conf.ProcessTakeProfiter.Start(ctx)
+
+		logger.Debug("Starting circuit breaker process")
+		conf.ProcessCircuitBreaker.Start(ctx)
+
+		logger.Debug("Starting pause watcher process")
+		conf.ProcessPauseWatcher.Start(ctx)
 	} else {
 		logger.Warn("Will not start jump finder process")
 	}
 
+	logger.Debug("Starting virtual trader process")
+	conf.ProcessVirtualTrader.Start(ctx)
+
 	if ok, _ := strconv.ParseBool(os.Getenv("NO_PRICE_GETTER")); !ok {
 		logger.Debug("Starting coins price getter process")
 		conf.ProcessPriceGetter.Start(ctx)
@@ -83,6 +110,18 @@ func main() {
 	logger.Debug("Starting cleaner process")
 	conf.ProcessCleaner.Start(ctx)
 
+	logger.Debug("Starting backuper process")
+	conf.ProcessBackuper.Start(ctx)
+
+	logger.Debug("Starting archiver process")
+	conf.ProcessArchiver.Start(ctx)
+
+	logger.Debug("Starting universe manager process")
+	conf.ProcessUniverseManager.Start(ctx)
+
+	logger.Debug("Starting losing coin guard process")
+	conf.ProcessLosingCoinGuard.Start(ctx)
+
 	logger.Debug("Starting save balance process")
 	conf.BalanceSaver.Start(ctx)
 
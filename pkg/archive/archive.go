@@ -0,0 +1,239 @@
+package archive
+
+import (
+	"compress/gzip"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+const (
+	partitionExt    = ".csv.gz"
+	partitionLayout = "2006-01"
+)
+
+// History moved out of the DB, as gzipped CSV files: <dir>/<table>/<YYYY-MM>.csv.gz
+type Archive struct {
+	Dir string
+}
+
+func New(dir string) *Archive {
+	return &Archive{Dir: dir}
+}
+
+// Rows of a table for one month (UTC)
+type Partition struct {
+	Table string
+	Month time.Time
+	Path  string
+}
+
+func (p Partition) End() time.Time {
+	return p.Month.AddDate(0, 1, 0)
+}
+
+func MonthOf(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+func (a *Archive) partitionPath(table string, month time.Time) string {
+	return filepath.Join(a.Dir, table, month.Format(partitionLayout)+partitionExt)
+}
+
+// Partitions of the table, oldest first
+func (a *Archive) Partitions(table string) ([]Partition, error) {
+	entries, err := os.ReadDir(filepath.Join(a.Dir, table))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var res []Partition
+	for _, entry := range entries {
+		name, found := strings.CutSuffix(entry.Name(), partitionExt)
+		if entry.IsDir() || !found {
+			continue
+		}
+		month, err := time.Parse(partitionLayout, name)
+		if err != nil {
+			continue
+		}
+		res = append(res, Partition{Table: table, Month: month, Path: filepath.Join(a.Dir, table, entry.Name())})
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Month.Before(res[j].Month) })
+
+	return res, nil
+}
+
+// Rows of the partitions ending after the date. Rows are not filtered, a partition can have older ones
+func Read[T schema.Tabler](a *Archive, db *gorm.DB, since time.Time) ([]T, error) {
+	var zero T
+	partitions, err := a.Partitions(zero.TableName())
+	if err != nil {
+		return nil, err
+	}
+
+	var res []T
+	for _, partition := range partitions {
+		if !partition.End().After(since) {
+			continue
+		}
+		rows, err := readPartition[T](db, partition.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed reading %s: %w", partition.Path, err)
+		}
+		res = append(res, rows...)
+	}
+	return res, nil
+}
+
+// Rows of one partition of the table
+func ReadPartition[T schema.Tabler](db *gorm.DB, partition Partition) ([]T, error) {
+	rows, err := readPartition[T](db, partition.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading %s: %w", partition.Path, err)
+	}
+	return rows, nil
+}
+
+// Rows of the oldest partition of the table
+func ReadFirst[T schema.Tabler](a *Archive, db *gorm.DB) ([]T, error) {
+	var zero T
+	partitions, err := a.Partitions(zero.TableName())
+	if err != nil || len(partitions) == 0 {
+		return nil, err
+	}
+	return readPartition[T](db, partitions[0].Path)
+}
+
+func readPartition[T schema.Tabler](db *gorm.DB, path string) ([]T, error) {
+	c, err := newCodec[T](db)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	r := csv.NewReader(gz)
+	header, err := r.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var res []T
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		var row T
+		if err := c.decode(header, record, &row); err != nil {
+			return nil, err
+		}
+		res = append(res, row)
+	}
+	return res, nil
+}
+
+// Partition written next to its final path, to be moved in place once the rows are deleted from the DB
+type PendingPartition struct {
+	tmp  string
+	path string
+}
+
+// Move the partition in place
+func (p PendingPartition) Commit() error {
+	return os.Rename(p.tmp, p.path)
+}
+
+func (p PendingPartition) Abort() {
+	_ = os.Remove(p.tmp)
+}
+
+// Write the rows of a month, merged with the ones already archived for that month
+func Prepare[T schema.Tabler](a *Archive, db *gorm.DB, month time.Time, rows []T) (PendingPartition, error) {
+	var zero T
+	path := a.partitionPath(zero.TableName(), month)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return PendingPartition{}, err
+	}
+
+	if _, err := os.Stat(path); err == nil {
+		existing, err := readPartition[T](db, path)
+		if err != nil {
+			return PendingPartition{}, fmt.Errorf("failed reading existing %s: %w", path, err)
+		}
+		rows = append(existing, rows...)
+	}
+
+	pending := PendingPartition{tmp: path + ".tmp", path: path}
+	if err := writePartition(db, pending.tmp, rows); err != nil {
+		pending.Abort()
+		return PendingPartition{}, err
+	}
+	return pending, nil
+}
+
+func writePartition[T schema.Tabler](db *gorm.DB, path string, rows []T) (err error) {
+	c, err := newCodec[T](db)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	gz := gzip.NewWriter(file)
+	w := csv.NewWriter(gz)
+	if err := w.Write(c.header()); err != nil {
+		return err
+	}
+	for i := range rows {
+		record, err := c.encode(&rows[i])
+		if err != nil {
+			return err
+		}
+		if err := w.Write(record); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
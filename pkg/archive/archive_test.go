@@ -0,0 +1,72 @@
+package archive_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/erwanlbp/trading-bot/pkg/archive"
+	"github.com/erwanlbp/trading-bot/pkg/model"
+)
+
+func TestArchiveRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	database, err := gorm.Open(sqlite.Open("file:"+filepath.Join(t.TempDir(), "test.db")), &gorm.Config{})
+	require.NoError(t, err)
+	a := archive.New(t.TempDir())
+
+	price := func(day int, p string) model.CoinPrice {
+		return model.CoinPrice{
+			Coin:       "ETH",
+			AltCoin:    "USDT",
+			Timestamp:  time.Date(2024, 1, day, 12, 30, 0, 0, time.UTC),
+			Price:      decimal.RequireFromString(p),
+			Averaged:   true,
+			Resolution: time.Hour,
+			Samples:    60,
+		}
+	}
+	january := []model.CoinPrice{price(1, "2200.5"), price(2, "2250")}
+	december := model.CoinPrice{Coin: "BTC", AltCoin: "USDT", Timestamp: time.Date(2023, 12, 31, 23, 0, 0, 0, time.UTC), Price: decimal.NewFromInt(42000)}
+
+	for month, rows := range map[time.Time][]model.CoinPrice{
+		archive.MonthOf(january[0].Timestamp): january[:1],
+		archive.MonthOf(december.Timestamp):   {december},
+	} {
+		pending, err := archive.Prepare(a, database, month, rows)
+		require.NoError(t, err)
+		require.NoError(t, pending.Commit())
+	}
+	// Merged with the rows already archived for the month
+	pending, err := archive.Prepare(a, database, archive.MonthOf(january[1].Timestamp), january[1:])
+	require.NoError(t, err)
+	require.NoError(t, pending.Commit())
+
+	partitions, err := a.Partitions(model.CoinPriceTableName)
+	require.NoError(t, err)
+	require.Len(t, partitions, 2)
+	assert.Equal(t, "2023-12", partitions[0].Month.Format("2006-01"))
+
+	res, err := archive.Read[model.CoinPrice](a, database, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Len(t, res, 2)
+	for i := range res {
+		assert.True(t, january[i].Timestamp.Equal(res[i].Timestamp))
+		assert.True(t, january[i].Price.Equal(res[i].Price))
+		assert.Equal(t, january[i].Resolution, res[i].Resolution)
+		assert.Equal(t, january[i].Samples, res[i].Samples)
+		assert.True(t, res[i].Averaged)
+	}
+
+	first, err := archive.ReadFirst[model.CoinPrice](a, database)
+	require.NoError(t, err)
+	require.Len(t, first, 1)
+	assert.Equal(t, "BTC", first[0].Coin)
+}
@@ -0,0 +1,160 @@
+package archive
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+	decimalType  = reflect.TypeOf(decimal.Decimal{})
+
+	schemaCache sync.Map
+)
+
+// CSV columns of a model, the columns of its gorm schema
+type codec struct {
+	fields []*schema.Field
+}
+
+func newCodec[T schema.Tabler](db *gorm.DB) (*codec, error) {
+	var zero T
+	s, err := schema.Parse(&zero, &schemaCache, db.NamingStrategy)
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing schema of %s: %w", zero.TableName(), err)
+	}
+
+	var res codec
+	for _, name := range s.DBNames {
+		res.fields = append(res.fields, s.FieldsByDBName[name])
+	}
+	return &res, nil
+}
+
+func (c *codec) header() []string {
+	var res []string
+	for _, field := range c.fields {
+		res = append(res, field.DBName)
+	}
+	return res
+}
+
+func (c *codec) encode(row interface{}) ([]string, error) {
+	value := reflect.ValueOf(row).Elem()
+
+	var res []string
+	for _, field := range c.fields {
+		s, err := encodeValue(field.ReflectValueOf(context.Background(), value))
+		if err != nil {
+			return nil, fmt.Errorf("column %s: %w", field.DBName, err)
+		}
+		res = append(res, s)
+	}
+	return res, nil
+}
+
+// Columns missing from the header keep their zero value, so older files can still be read after a column is added
+func (c *codec) decode(header, record []string, row interface{}) error {
+	value := reflect.ValueOf(row).Elem()
+
+	index := make(map[string]int)
+	for i, name := range header {
+		index[name] = i
+	}
+
+	for _, field := range c.fields {
+		i, ok := index[field.DBName]
+		if !ok || i >= len(record) {
+			continue
+		}
+		if err := decodeValue(record[i], field.ReflectValueOf(context.Background(), value)); err != nil {
+			return fmt.Errorf("column %s: %w", field.DBName, err)
+		}
+	}
+	return nil
+}
+
+func encodeValue(v reflect.Value) (string, error) {
+	switch v.Type() {
+	case timeType:
+		return v.Interface().(time.Time).UTC().Format(time.RFC3339Nano), nil
+	case decimalType:
+		return v.Interface().(decimal.Decimal).String(), nil
+	case durationType:
+		return strconv.FormatInt(v.Int(), 10), nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64), nil
+	}
+	return "", fmt.Errorf("unsupported type %s", v.Type())
+}
+
+func decodeValue(s string, v reflect.Value) error {
+	switch v.Type() {
+	case timeType:
+		t, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	case decimalType:
+		d, err := decimal.NewFromString(s)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return err
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return err
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
@@ -0,0 +1,252 @@
+package binancetest
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/adshao/go-binance/v2"
+	"github.com/shopspring/decimal"
+)
+
+// Stub of the Binance API endpoints used to trade, orders go through OrderStatuses instead of a matching engine.
+//
+// Fields must be set before sending requests
+type Server struct {
+	*httptest.Server
+
+	// Free balance by asset
+	Balances map[string]string
+	// Price by symbol, also used as bid and ask. Only these symbols exist
+	Prices map[string]string
+	// Status returned by each status check of an order, the last one is repeated. FILLED if empty
+	OrderStatuses []binance.OrderStatusType
+	// Order creations fail with this message when set
+	RejectOrders string
+
+	mtx    sync.Mutex
+	orders map[int64]*order
+	nextID int64
+}
+
+type order struct {
+	binance.Order
+	checks int
+}
+
+func NewServer(t testing.TB) *Server {
+	s := &Server{
+		Balances: map[string]string{},
+		Prices:   map[string]string{},
+		orders:   map[int64]*order{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/account", s.account)
+	mux.HandleFunc("/api/v3/ticker/price", s.prices)
+	mux.HandleFunc("/api/v3/ticker/bookTicker", s.bookTickers)
+	mux.HandleFunc("/api/v3/exchangeInfo", s.exchangeInfo)
+	mux.HandleFunc("/api/v3/order", s.order)
+
+	s.Server = httptest.NewServer(mux)
+	t.Cleanup(s.Server.Close)
+
+	return s
+}
+
+func (s *Server) account(w http.ResponseWriter, _ *http.Request) {
+	var res binance.Account
+	for asset, free := range s.Balances {
+		res.Balances = append(res.Balances, binance.Balance{Asset: asset, Free: free, Locked: "0"})
+	}
+	reply(w, res)
+}
+
+func (s *Server) prices(w http.ResponseWriter, r *http.Request) {
+	var wanted []string
+	if symbol := r.URL.Query().Get("symbol"); symbol != "" {
+		wanted = []string{symbol}
+	} else if symbols := r.URL.Query().Get("symbols"); symbols != "" {
+		if err := json.Unmarshal([]byte(symbols), &wanted); err != nil {
+			fail(w, -1100, err.Error())
+			return
+		}
+	} else {
+		for symbol := range s.Prices {
+			wanted = append(wanted, symbol)
+		}
+	}
+
+	var res []binance.SymbolPrice
+	for _, symbol := range wanted {
+		price, ok := s.Prices[symbol]
+		if !ok {
+			fail(w, -1121, "Invalid symbol.")
+			return
+		}
+		res = append(res, binance.SymbolPrice{Symbol: symbol, Price: price})
+	}
+	reply(w, res)
+}
+
+func (s *Server) bookTickers(w http.ResponseWriter, _ *http.Request) {
+	var res []binance.BookTicker
+	for symbol, price := range s.Prices {
+		res = append(res, binance.BookTicker{Symbol: symbol, BidPrice: price, BidQuantity: "1000", AskPrice: price, AskQuantity: "1000"})
+	}
+	reply(w, res)
+}
+
+func (s *Server) exchangeInfo(w http.ResponseWriter, _ *http.Request) {
+	var res binance.ExchangeInfo
+	for symbol := range s.Prices {
+		res.Symbols = append(res.Symbols, binance.Symbol{
+			Symbol:         symbol,
+			Status:         "TRADING",
+			QuotePrecision: 8,
+			Filters: []map[string]interface{}{
+				{"filterType": string(binance.SymbolFilterTypeLotSize), "minQty": "0.001", "maxQty": "100000", "stepSize": "0.001"},
+			},
+		})
+	}
+	reply(w, res)
+}
+
+func (s *Server) order(w http.ResponseWriter, r *http.Request) {
+	params, err := requestParams(r)
+	if err != nil {
+		fail(w, -1100, err.Error())
+		return
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if r.Method == http.MethodPost {
+		s.createOrder(w, params)
+		return
+	}
+
+	id, _ := strconv.ParseInt(params.Get("orderId"), 10, 64)
+	o, ok := s.orders[id]
+	if !ok {
+		fail(w, -2013, "Order does not exist.")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		status := binance.OrderStatusTypeFilled
+		if len(s.OrderStatuses) > 0 {
+			status = s.OrderStatuses[min(o.checks, len(s.OrderStatuses)-1)]
+		}
+		o.checks++
+		o.setStatus(status)
+		reply(w, o.Order)
+	case http.MethodDelete:
+		o.setStatus(binance.OrderStatusTypeCanceled)
+		reply(w, binance.CancelOrderResponse{
+			Symbol:                   o.Symbol,
+			OrderID:                  o.OrderID,
+			TransactTime:             time.Now().UnixMilli(),
+			Price:                    o.Price,
+			OrigQuantity:             o.OrigQuantity,
+			ExecutedQuantity:         o.ExecutedQuantity,
+			CummulativeQuoteQuantity: o.CummulativeQuoteQuantity,
+			Status:                   o.Status,
+			Side:                     o.Side,
+		})
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) createOrder(w http.ResponseWriter, params url.Values) {
+	if s.RejectOrders != "" {
+		fail(w, -2010, s.RejectOrders)
+		return
+	}
+
+	s.nextID++
+	o := &order{Order: binance.Order{
+		Symbol:                   params.Get("symbol"),
+		OrderID:                  s.nextID,
+		Price:                    params.Get("price"),
+		OrigQuantity:             params.Get("quantity"),
+		ExecutedQuantity:         "0",
+		CummulativeQuoteQuantity: "0",
+		Status:                   binance.OrderStatusTypeNew,
+		TimeInForce:              binance.TimeInForceType(params.Get("timeInForce")),
+		Type:                     binance.OrderType(params.Get("type")),
+		Side:                     binance.SideType(params.Get("side")),
+		Time:                     time.Now().UnixMilli(),
+	}}
+	s.orders[o.OrderID] = o
+
+	reply(w, binance.CreateOrderResponse{
+		Symbol:                   o.Symbol,
+		OrderID:                  o.OrderID,
+		TransactTime:             o.Time,
+		Price:                    o.Price,
+		OrigQuantity:             o.OrigQuantity,
+		ExecutedQuantity:         o.ExecutedQuantity,
+		CummulativeQuoteQuantity: o.CummulativeQuoteQuantity,
+		Status:                   o.Status,
+		TimeInForce:              o.TimeInForce,
+		Type:                     o.Type,
+		Side:                     o.Side,
+	})
+}
+
+// Filled orders are executed at their limit price, partially filled ones for half of the quantity
+func (o *order) setStatus(status binance.OrderStatusType) {
+	if o.Status == binance.OrderStatusTypeCanceled || o.Status == binance.OrderStatusTypeFilled {
+		return
+	}
+	o.Status = status
+	o.UpdateTime = time.Now().UnixMilli()
+
+	quantity := decimal.RequireFromString(o.OrigQuantity)
+	switch status {
+	case binance.OrderStatusTypeFilled:
+	case binance.OrderStatusTypePartiallyFilled:
+		quantity = quantity.Div(decimal.NewFromInt(2))
+	default:
+		return
+	}
+	o.ExecutedQuantity = quantity.String()
+	o.CummulativeQuoteQuantity = quantity.Mul(decimal.RequireFromString(o.Price)).String()
+}
+
+// Query and body params, the body of DELETE requests isn't parsed by http.Request.ParseForm
+func requestParams(r *http.Request) (url.Values, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	params, err := url.ParseQuery(string(body))
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range r.URL.Query() {
+		params[key] = append(params[key], values...)
+	}
+	return params, nil
+}
+
+func reply(w http.ResponseWriter, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func fail(w http.ResponseWriter, code int64, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"code": code, "msg": msg})
+}
@@ -3,10 +3,12 @@ package binance
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"sync/atomic"
 	"time"
 
 	"github.com/adshao/go-binance/v2"
+	"github.com/shopspring/decimal"
 	"go.uber.org/zap"
 
 	"github.com/erwanlbp/trading-bot/pkg/config/configfile"
@@ -20,37 +22,58 @@ type SymbolBlackListGetter interface {
 	IsSymbolBlacklisted(symbol string) bool
 }
 
+type BridgeReserveCapper interface {
+	// Bridge amount that must not be spent buying coins, capped at the bridge balance
+	CapBridgeReserve(balance decimal.Decimal) (decimal.Decimal, error)
+}
+
 type Client struct {
 	client          *binance.Client
 	Logger          *log.Logger
 	ConfigFile      *configfile.ConfigFile
 	EventBus        *eventbus.Bus
 	SymbolBlackList SymbolBlackListGetter
+	BridgeReserve   BridgeReserveCapper
 
 	tradeInProgress atomic.Bool
+	weight          *weightTracker
 
-	coinInfosRefresher *refresher.Refresher[map[string]binance.Symbol]
+	coinInfosRefresher   *refresher.Refresher[map[string]binance.Symbol]
+	tickerStatsRefresher *refresher.Refresher[map[string]TickerStats]
 }
 
-func NewClient(l *log.Logger, cf *configfile.ConfigFile, eb *eventbus.Bus, sbg SymbolBlackListGetter) *Client {
+func NewClient(l *log.Logger, cf *configfile.ConfigFile, eb *eventbus.Bus, sbg SymbolBlackListGetter, brg BridgeReserveCapper) *Client {
 	if cf.TestMode {
 		l.Info("Activating Binance test mode")
 		binance.UseTestnet = true
 	}
 
+	weight := &weightTracker{base: http.DefaultTransport}
 	client := Client{
 		client:          binance.NewClient(cf.Binance.APIKey, cf.Binance.APIKeySecret),
+		weight:          weight,
 		Logger:          l,
 		ConfigFile:      cf,
 		EventBus:        eb,
 		SymbolBlackList: sbg,
+		BridgeReserve:   brg,
 	}
 
+	// Not modifying http.DefaultClient, used by default
+	client.client.HTTPClient = &http.Client{Transport: weight}
+
 	client.coinInfosRefresher = refresher.NewRefresher(l, 5*time.Minute, client.RefreshSymbolInfos, refresher.OnErrorLog(client.Logger))
+	client.tickerStatsRefresher = refresher.NewRefresher(l, 5*time.Minute, client.RefreshTickerStats, refresher.OnErrorLog(client.Logger))
 
 	return &client
 }
 
+// Refresh symbol infos and ticker stats in background until the context is done
+func (c *Client) StartRefreshers(ctx context.Context) {
+	c.coinInfosRefresher.Start(ctx)
+	c.tickerStatsRefresher.Start(ctx)
+}
+
 func (c *Client) LogBalances(ctx context.Context) {
 	b, err := c.GetBalance(ctx, append(c.ConfigFile.Coins, c.ConfigFile.Bridge)...)
 	if err != nil {
@@ -59,3 +82,8 @@ func (c *Client) LogBalances(ctx context.Context) {
 		c.Logger.Info(fmt.Sprintf("Balances are %s", util.ToJSON(b)))
 	}
 }
+
+// Send the API calls to another endpoint, like a stub server in tests
+func (c *Client) SetAPIEndpoint(url string) {
+	c.client.SetApiEndpoint(url)
+}
@@ -20,6 +20,13 @@ type CoinPrice struct {
 	Timestamp time.Time
 }
 
+type BookTicker struct {
+	Coin     string
+	AltCoin  string
+	BidPrice decimal.Decimal
+	AskPrice decimal.Decimal
+}
+
 type CoinPriceGroupByAlt struct {
 	Coin   string
 	Prices []CoinPrice
@@ -65,6 +72,49 @@ func (c *Client) GetCoinsPrice(ctx context.Context, coins, altCoins []string) (m
 	return res, nil
 }
 
+// GetCoinsBookTicker returns the best bid/ask of each symbol, indexed by symbol
+func (c *Client) GetCoinsBookTicker(ctx context.Context, coins, altCoins []string) (map[string]BookTicker, error) {
+
+	symbols := util.AsSet(getSymbols(coins, altCoins, c.SymbolBlackList), util.Identity[string]())
+
+	if len(symbols) == 0 {
+		return nil, fmt.Errorf("no symbols found")
+	}
+
+	// The endpoint only accepts one symbol, or none to get all of them. One call for all is lighter than one call per symbol
+	tickers, err := c.client.NewListBookTickersService().Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var res = make(map[string]BookTicker)
+	for _, ticker := range tickers {
+		if !symbols[ticker.Symbol] {
+			continue
+		}
+		coin, altCoin, err := util.Unsymbol(ticker.Symbol, coins, altCoins)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't unsymbol %s: %w", ticker.Symbol, err)
+		}
+		bid, err := decimal.NewFromString(ticker.BidPrice)
+		if err != nil {
+			return nil, fmt.Errorf("failed parsing bid price for %s(%s): %w", ticker.Symbol, ticker.BidPrice, err)
+		}
+		ask, err := decimal.NewFromString(ticker.AskPrice)
+		if err != nil {
+			return nil, fmt.Errorf("failed parsing ask price for %s(%s): %w", ticker.Symbol, ticker.AskPrice, err)
+		}
+		res[ticker.Symbol] = BookTicker{
+			Coin:     coin,
+			AltCoin:  altCoin,
+			BidPrice: bid,
+			AskPrice: ask,
+		}
+	}
+
+	return res, nil
+}
+
 func (c *Client) GetSymbolPriceAtTime(ctx context.Context, coin, altCoin string, date time.Time) (CoinPrice, error) {
 
 	symbols := getSymbols([]string{coin}, []string{altCoin}, c.SymbolBlackList)
@@ -161,7 +211,7 @@ func (c *Client) dichotomicPriceFetching(ctx context.Context, symbols []string)
 		if err != nil {
 			if ErrorIs(err, BinanceErrorInvalidSymbol) {
 				c.Logger.Info(fmt.Sprintf("Found unexisting symbol '%s' on Binance, won't fetch it anymore", symbol))
-				c.EventBus.Notify(eventbus.FoundUnexistingSymbol(symbol))
+				eventbus.Publish(c.EventBus, eventbus.FoundUnexistingSymbol, symbol)
 				continue
 			}
 			return nil, fmt.Errorf("failed to fetch price for symbol %s: %w", symbol, err)
@@ -0,0 +1,154 @@
+package binance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/erwanlbp/trading-bot/pkg/util"
+)
+
+var ErrNotEnoughLiquidity = errors.New("not_enough_liquidity")
+
+type PriceLevel struct {
+	Price    decimal.Decimal
+	Quantity decimal.Decimal
+}
+
+type OrderBook struct {
+	Symbol string
+	// Best first : highest bids, lowest asks
+	Bids []PriceLevel
+	Asks []PriceLevel
+}
+
+func (c *Client) GetOrderBook(ctx context.Context, symbol string) (OrderBook, error) {
+	depth, err := c.client.NewDepthService().Symbol(symbol).Limit(c.ConfigFile.Order.BookDepth).Do(ctx)
+	if err != nil {
+		return OrderBook{}, err
+	}
+
+	res := OrderBook{Symbol: symbol}
+	for _, bid := range depth.Bids {
+		level, err := parsePriceLevel(bid.Price, bid.Quantity)
+		if err != nil {
+			return OrderBook{}, fmt.Errorf("failed parsing bid for %s: %w", symbol, err)
+		}
+		res.Bids = append(res.Bids, level)
+	}
+	for _, ask := range depth.Asks {
+		level, err := parsePriceLevel(ask.Price, ask.Quantity)
+		if err != nil {
+			return OrderBook{}, fmt.Errorf("failed parsing ask for %s: %w", symbol, err)
+		}
+		res.Asks = append(res.Asks, level)
+	}
+
+	return res, nil
+}
+
+func parsePriceLevel(price, quantity string) (PriceLevel, error) {
+	p, err := decimal.NewFromString(price)
+	if err != nil {
+		return PriceLevel{}, fmt.Errorf("invalid price '%s': %w", price, err)
+	}
+	q, err := decimal.NewFromString(quantity)
+	if err != nil {
+		return PriceLevel{}, fmt.Errorf("invalid quantity '%s': %w", quantity, err)
+	}
+	return PriceLevel{Price: p, Quantity: q}, nil
+}
+
+// Volume weighted price we'd get selling quantity (in coin) on the bids
+func (b OrderBook) SellVWAP(quantity decimal.Decimal) (decimal.Decimal, error) {
+	if len(b.Bids) == 0 || !quantity.IsPositive() {
+		return decimal.Zero, ErrNotEnoughLiquidity
+	}
+
+	remaining := quantity
+	var quote decimal.Decimal
+	for _, level := range b.Bids {
+		filled := decimal.Min(remaining, level.Quantity)
+		quote = quote.Add(filled.Mul(level.Price))
+		remaining = remaining.Sub(filled)
+		if remaining.IsZero() {
+			return quote.Div(quantity), nil
+		}
+	}
+
+	return decimal.Zero, ErrNotEnoughLiquidity
+}
+
+// Volume weighted price we'd pay spending quoteQuantity (in alt coin) on the asks
+func (b OrderBook) BuyVWAP(quoteQuantity decimal.Decimal) (decimal.Decimal, error) {
+	if len(b.Asks) == 0 || !quoteQuantity.IsPositive() {
+		return decimal.Zero, ErrNotEnoughLiquidity
+	}
+
+	remaining := quoteQuantity
+	var bought decimal.Decimal
+	for _, level := range b.Asks {
+		levelQuote := level.Quantity.Mul(level.Price)
+		if remaining.LessThanOrEqual(levelQuote) {
+			bought = bought.Add(remaining.Div(level.Price))
+			return quoteQuantity.Div(bought), nil
+		}
+		bought = bought.Add(level.Quantity)
+		remaining = remaining.Sub(levelQuote)
+	}
+
+	return decimal.Zero, ErrNotEnoughLiquidity
+}
+
+type SlippageEstimate struct {
+	BestBid  decimal.Decimal // Best bid of from_coin/bridge
+	BestAsk  decimal.Decimal // Best ask of to_coin/bridge
+	SellVWAP decimal.Decimal
+	BuyVWAP  decimal.Decimal
+}
+
+// Ratio (between 0 and 1) of the value lost by walking both books, compared to trading everything at best bid/ask
+func (e SlippageEstimate) Slippage() decimal.Decimal {
+	if e.BestBid.IsZero() || e.BuyVWAP.IsZero() {
+		return decimal.Zero
+	}
+	return decimal.NewFromInt(1).Sub(e.SellVWAP.Div(e.BestBid).Mul(e.BestAsk.Div(e.BuyVWAP)))
+}
+
+// Same as Slippage() but with the prices really obtained by the orders
+func (e SlippageEstimate) RealizedSlippage(sellPrice, buyPrice decimal.Decimal) decimal.Decimal {
+	if e.BestBid.IsZero() || buyPrice.IsZero() {
+		return decimal.Zero
+	}
+	return decimal.NewFromInt(1).Sub(sellPrice.Div(e.BestBid).Mul(e.BestAsk.Div(buyPrice)))
+}
+
+// Estimate the slippage of selling quantity of fromCoin to the bridge, and buying toCoin with all of it
+func (c *Client) EstimateJumpSlippage(ctx context.Context, fromCoin, toCoin, bridge string, quantity decimal.Decimal) (SlippageEstimate, error) {
+	sellBook, err := c.GetOrderBook(ctx, util.Symbol(fromCoin, bridge))
+	if err != nil {
+		return SlippageEstimate{}, fmt.Errorf("failed to get sell order book: %w", err)
+	}
+	buyBook, err := c.GetOrderBook(ctx, util.Symbol(toCoin, bridge))
+	if err != nil {
+		return SlippageEstimate{}, fmt.Errorf("failed to get buy order book: %w", err)
+	}
+
+	sellVWAP, err := sellBook.SellVWAP(quantity)
+	if err != nil {
+		return SlippageEstimate{}, fmt.Errorf("can't sell %s %s: %w", quantity, fromCoin, err)
+	}
+	buyVWAP, err := buyBook.BuyVWAP(quantity.Mul(sellVWAP))
+	if err != nil {
+		return SlippageEstimate{}, fmt.Errorf("can't buy %s: %w", toCoin, err)
+	}
+
+	return SlippageEstimate{
+		BestBid:  sellBook.Bids[0].Price,
+		BestAsk:  buyBook.Asks[0].Price,
+		SellVWAP: sellVWAP,
+		BuyVWAP:  buyVWAP,
+	}, nil
+}
@@ -0,0 +1,73 @@
+package binance_test
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/erwanlbp/trading-bot/pkg/binance"
+)
+
+func level(price, quantity string) binance.PriceLevel {
+	return binance.PriceLevel{Price: decimal.RequireFromString(price), Quantity: decimal.RequireFromString(quantity)}
+}
+
+func TestOrderBookVWAP(t *testing.T) {
+	t.Parallel()
+
+	book := binance.OrderBook{
+		Bids: []binance.PriceLevel{level("10", "1"), level("9", "2")},
+		Asks: []binance.PriceLevel{level("11", "1"), level("12", "2")},
+	}
+
+	for _, c := range []struct {
+		name     string
+		sell     bool
+		quantity string
+		expected string
+		err      error
+	}{
+		{name: "sell first level", sell: true, quantity: "0.5", expected: "10"},
+		{name: "sell walking the book", sell: true, quantity: "2", expected: "9.5"},
+		{name: "sell too much", sell: true, quantity: "4", err: binance.ErrNotEnoughLiquidity},
+		{name: "buy first level", quantity: "5.5", expected: "11"},
+		{name: "buy walking the book", quantity: "23", expected: "11.5"},
+		{name: "buy too much", quantity: "36", err: binance.ErrNotEnoughLiquidity},
+	} {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			var res decimal.Decimal
+			var err error
+			if c.sell {
+				res, err = book.SellVWAP(decimal.RequireFromString(c.quantity))
+			} else {
+				res, err = book.BuyVWAP(decimal.RequireFromString(c.quantity))
+			}
+
+			if c.err != nil {
+				assert.ErrorIs(t, err, c.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, c.expected, res.String())
+		})
+	}
+}
+
+func TestSlippage(t *testing.T) {
+	t.Parallel()
+
+	estimate := binance.SlippageEstimate{
+		BestBid:  decimal.NewFromInt(10),
+		BestAsk:  decimal.NewFromInt(10),
+		SellVWAP: decimal.NewFromInt(9),
+		BuyVWAP:  decimal.NewFromInt(10),
+	}
+
+	assert.Equal(t, "0.1", estimate.Slippage().String())
+	assert.Equal(t, "0", estimate.RealizedSlippage(decimal.NewFromInt(10), decimal.NewFromInt(10)).String())
+	assert.True(t, binance.SlippageEstimate{}.Slippage().IsZero())
+}
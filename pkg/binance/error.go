@@ -9,6 +9,8 @@ import (
 const (
 	BinanceErrorInvalidSymbol   int64 = -1121
 	BinanceErrorInvalidQuantity int64 = -1013
+	// Request weight limit reached (HTTP 429), or IP banned for a while (HTTP 418)
+	BinanceErrorTooManyRequests int64 = -1003
 )
 
 var ErrNoPriceFoundAtTime = errors.New("no_price_found_at_time")
@@ -0,0 +1,66 @@
+package binance
+
+import (
+	"time"
+
+	"github.com/adshao/go-binance/v2"
+	"github.com/shopspring/decimal"
+
+	"github.com/erwanlbp/trading-bot/pkg/eventbus"
+)
+
+// State of an order when something happened to it
+type OrderEvent struct {
+	Timestamp time.Time
+	OrderID   int64
+	Symbol    string
+	Side      string
+	Status    string
+	// Limit price and ordered quantity
+	Price    decimal.Decimal
+	Quantity decimal.Decimal
+	// Executed so far, and the bridge (quote) amount it represents
+	ExecutedQuantity decimal.Decimal
+	QuoteQuantity    decimal.Decimal
+	// Why the order is cancelled
+	Reason string
+}
+
+var (
+	OrderPlaced = eventbus.NewTopic[OrderEvent]("order_placed")
+	// Status or executed quantity changed while waiting for the order completion
+	OrderUpdated   = eventbus.NewTopic[OrderEvent]("order_updated")
+	OrderFilled    = eventbus.NewTopic[OrderEvent]("order_filled")
+	OrderCancelled = eventbus.NewTopic[OrderEvent]("order_cancelled")
+)
+
+func newOrderEvent(symbol string, orderID int64, side binance.SideType, status binance.OrderStatusType, price, quantity, executed, quote string) OrderEvent {
+	return OrderEvent{
+		Timestamp:        time.Now().UTC(),
+		OrderID:          orderID,
+		Symbol:           symbol,
+		Side:             string(side),
+		Status:           string(status),
+		Price:            decimalOrZero(price),
+		Quantity:         decimalOrZero(quantity),
+		ExecutedQuantity: decimalOrZero(executed),
+		QuoteQuantity:    decimalOrZero(quote),
+	}
+}
+
+func createdOrderEvent(o *binance.CreateOrderResponse) OrderEvent {
+	return newOrderEvent(o.Symbol, o.OrderID, o.Side, o.Status, o.Price, o.OrigQuantity, o.ExecutedQuantity, o.CummulativeQuoteQuantity)
+}
+
+func orderEvent(o *binance.Order) OrderEvent {
+	return newOrderEvent(o.Symbol, o.OrderID, o.Side, o.Status, o.Price, o.OrigQuantity, o.ExecutedQuantity, o.CummulativeQuoteQuantity)
+}
+
+func cancelledOrderEvent(o *binance.CancelOrderResponse, reason string) OrderEvent {
+	return withReason(newOrderEvent(o.Symbol, o.OrderID, o.Side, o.Status, o.Price, o.OrigQuantity, o.ExecutedQuantity, o.CummulativeQuoteQuantity), reason)
+}
+
+func withReason(e OrderEvent, reason string) OrderEvent {
+	e.Reason = reason
+	return e
+}
@@ -0,0 +1,104 @@
+package binance_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	gobinance "github.com/adshao/go-binance/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/erwanlbp/trading-bot/pkg/binance"
+	"github.com/erwanlbp/trading-bot/pkg/binance/binancetest"
+	"github.com/erwanlbp/trading-bot/pkg/config/configfile"
+	"github.com/erwanlbp/trading-bot/pkg/eventbus"
+	"github.com/erwanlbp/trading-bot/pkg/log"
+)
+
+// Name, status and reason of the order events, in the order they were published
+func orderEvents(sub *eventbus.Subscription) []string {
+	var res []string
+	ctx, cancel := context.WithCancel(context.Background())
+	sub.Handler(ctx, func(_ context.Context, e eventbus.Event) {
+		event := e.Payload.(binance.OrderEvent)
+		res = append(res, e.Name+" "+event.Status+" "+event.Reason)
+		if sub.Stats().Pending == 0 {
+			cancel()
+		}
+	})
+	return res
+}
+
+func TestTradeOrderEvents(t *testing.T) {
+	t.Parallel()
+
+	for _, c := range []struct {
+		name     string
+		statuses []gobinance.OrderStatusType
+		err      string
+		expected []string
+	}{
+		{
+			name:     "filled",
+			statuses: []gobinance.OrderStatusType{gobinance.OrderStatusTypeNew, gobinance.OrderStatusTypePartiallyFilled, gobinance.OrderStatusTypeFilled},
+			expected: []string{
+				"order_placed NEW ",
+				"order_updated NEW ",
+				"order_updated PARTIALLY_FILLED ",
+				"order_updated FILLED ",
+				"order_filled FILLED ",
+			},
+		},
+		{
+			name:     "canceled on the exchange",
+			statuses: []gobinance.OrderStatusType{gobinance.OrderStatusTypeNew, gobinance.OrderStatusTypeCanceled},
+			err:      "order got canceled",
+			expected: []string{
+				"order_placed NEW ",
+				"order_updated NEW ",
+				"order_updated CANCELED ",
+				"order_cancelled CANCELED canceled on the exchange",
+			},
+		},
+		{
+			name:     "timeout",
+			statuses: []gobinance.OrderStatusType{gobinance.OrderStatusTypeNew},
+			err:      "wait timeout reached",
+			expected: []string{
+				"order_placed NEW ",
+				"order_updated NEW ",
+				"order_cancelled CANCELED wait timeout reached",
+			},
+		},
+	} {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := binancetest.NewServer(t)
+			server.Balances = map[string]string{"ETH": "2", "USDT": "10"}
+			server.Prices = map[string]string{"ETHUSDT": "3000"}
+			server.OrderStatuses = c.statuses
+
+			cf := &configfile.ConfigFile{Bridge: "USDT", Coins: []string{"ETH"}, TradeTimeout: 200 * time.Millisecond}
+			cf.Order.Refresh = 10 * time.Millisecond
+
+			bus := eventbus.NewEventBus()
+			client := binance.NewClient(log.NewSimpleZapLogger(), cf, bus, nil, nil)
+			client.SetAPIEndpoint(server.URL)
+
+			sub := bus.Subscribe(binance.OrderPlaced.Name, binance.OrderUpdated.Name, binance.OrderFilled.Name, binance.OrderCancelled.Name)
+
+			res, err := client.Sell(context.Background(), "ETH", "USDT")
+			if c.err != "" {
+				assert.EqualError(t, err, c.err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, "2", res.Quantity().String())
+			}
+
+			assert.Equal(t, c.expected, orderEvents(sub))
+		})
+	}
+}
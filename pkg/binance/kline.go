@@ -0,0 +1,100 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/adshao/go-binance/v2"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	klinesPageSize       = 1000
+	klinesPageWeight     = 2
+	maxRateLimitBackoffs = 3
+)
+
+type Kline struct {
+	Coin     string
+	AltCoin  string
+	OpenTime time.Time
+	Open     decimal.Decimal
+	High     decimal.Decimal
+	Low      decimal.Decimal
+	Close    decimal.Decimal
+}
+
+// Klines opened in [start, end), fetched page by page within the bulk weight budget
+func (c *Client) GetKlines(ctx context.Context, coin, altCoin, interval string, start, end time.Time) ([]Kline, error) {
+	symbol := coin + altCoin
+	if c.SymbolBlackList.IsSymbolBlacklisted(symbol) {
+		return nil, fmt.Errorf("symbol %s is blacklisted", symbol)
+	}
+
+	var res []Kline
+	cursor := start
+	for cursor.Before(end) {
+		page, err := c.klinesPage(ctx, symbol, interval, cursor, end)
+		if err != nil {
+			return res, fmt.Errorf("failed getting %s klines from %s: %w", symbol, cursor.Format(time.DateTime), err)
+		}
+
+		for _, k := range page {
+			kline, err := parseKline(coin, altCoin, k)
+			if err != nil {
+				return res, fmt.Errorf("failed parsing %s kline: %w", symbol, err)
+			}
+			if !kline.OpenTime.Before(end) {
+				continue
+			}
+			res = append(res, kline)
+		}
+
+		if len(page) < klinesPageSize {
+			break
+		}
+		cursor = time.UnixMilli(page[len(page)-1].CloseTime + 1).UTC()
+	}
+
+	return res, nil
+}
+
+func (c *Client) klinesPage(ctx context.Context, symbol, interval string, start, end time.Time) ([]*binance.Kline, error) {
+	for backoffs := 0; ; backoffs++ {
+		if err := c.waitForWeight(ctx, klinesPageWeight); err != nil {
+			return nil, err
+		}
+
+		page, err := c.client.NewKlinesService().
+			Symbol(symbol).
+			Interval(interval).
+			StartTime(start.UnixMilli()).
+			EndTime(end.UnixMilli() - 1).
+			Limit(klinesPageSize).
+			Do(ctx)
+		if err == nil || !ErrorIs(err, BinanceErrorTooManyRequests) || backoffs == maxRateLimitBackoffs {
+			return page, err
+		}
+
+		c.Logger.Warn(fmt.Sprintf("Binance rate limit reached fetching %s klines, waiting for the next minute", symbol))
+		if err := sleepUntilNextMinute(ctx); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func parseKline(coin, altCoin string, k *binance.Kline) (Kline, error) {
+	res := Kline{Coin: coin, AltCoin: altCoin, OpenTime: time.UnixMilli(k.OpenTime).UTC()}
+	for _, field := range []struct {
+		raw   string
+		value *decimal.Decimal
+	}{{k.Open, &res.Open}, {k.High, &res.High}, {k.Low, &res.Low}, {k.Close, &res.Close}} {
+		d, err := decimal.NewFromString(field.raw)
+		if err != nil {
+			return Kline{}, err
+		}
+		*field.value = d
+	}
+	return res, nil
+}
@@ -0,0 +1,68 @@
+package binance
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	usedWeightHeader = "X-Mbx-Used-Weight-1m"
+	// Binance allows 6000 per minute and IP, bulk fetching keeps half for the bot
+	bulkWeightBudget int64 = 3000
+)
+
+// Request weight used in the current minute, read from the header of every response
+type weightTracker struct {
+	base   http.RoundTripper
+	used   atomic.Int64
+	minute atomic.Int64
+}
+
+func (t *weightTracker) RoundTrip(req *http.Request) (*http.Response, error) {
+	res, err := t.base.RoundTrip(req)
+	if err != nil {
+		return res, err
+	}
+	if used, err := strconv.ParseInt(res.Header.Get(usedWeightHeader), 10, 64); err == nil {
+		t.used.Store(used)
+		t.minute.Store(time.Now().Unix() / 60)
+	}
+	return res, nil
+}
+
+func (t *weightTracker) Used() int64 {
+	if t.minute.Load() != time.Now().Unix()/60 {
+		return 0
+	}
+	return t.used.Load()
+}
+
+// Weight used by all the calls of the bot in the current minute
+func (c *Client) UsedWeight() int64 {
+	return c.weight.Used()
+}
+
+// Block until the minute has room for a request of this weight, within the bulk budget
+func (c *Client) waitForWeight(ctx context.Context, weight int64) error {
+	for c.weight.Used()+weight > bulkWeightBudget {
+		if err := sleepUntilNextMinute(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Binance resets the weight every minute
+func sleepUntilNextMinute(ctx context.Context) error {
+	now := time.Now()
+	wait := now.Truncate(time.Minute).Add(time.Minute + time.Second).Sub(now)
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(wait):
+		return nil
+	}
+}
@@ -0,0 +1,93 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/erwanlbp/trading-bot/pkg/util"
+)
+
+// 24h rolling statistics of a symbol
+type TickerStats struct {
+	Symbol string
+	// Volume traded in the quote asset
+	QuoteVolume decimal.Decimal
+	PriceChange decimal.Decimal
+	HighPrice   decimal.Decimal
+	LowPrice    decimal.Decimal
+	LastPrice   decimal.Decimal
+	BidPrice    decimal.Decimal
+	AskPrice    decimal.Decimal
+	NbTrades    int64
+}
+
+// Spread between best ask and best bid (in %) of the mid price, zero if the book is unknown
+func (s TickerStats) Spread() decimal.Decimal {
+	if !s.BidPrice.IsPositive() || !s.AskPrice.IsPositive() {
+		return decimal.Zero
+	}
+	mid := s.BidPrice.Add(s.AskPrice).Div(decimal.NewFromInt(2))
+	return s.AskPrice.Sub(s.BidPrice).Div(mid).Mul(decimal.NewFromInt(100))
+}
+
+// Return why the symbol is not liquid enough, empty if it is. A zero limit is ignored
+func (s TickerStats) CheckLiquidity(minQuoteVolume, maxSpread decimal.Decimal) string {
+	if minQuoteVolume.IsPositive() && s.QuoteVolume.LessThan(minQuoteVolume) {
+		return fmt.Sprintf("24h volume %s < %s", s.QuoteVolume.StringFixed(0), minQuoteVolume)
+	}
+	if maxSpread.IsPositive() && s.Spread().GreaterThan(maxSpread) {
+		return fmt.Sprintf("spread %s %% > %s %%", s.Spread().StringFixed(3), maxSpread)
+	}
+	return ""
+}
+
+// Stats of all symbols, indexed by symbol
+func (c *Client) GetTickerStats(ctx context.Context) map[string]TickerStats {
+	return c.tickerStatsRefresher.Data(ctx)
+}
+
+// Return why the coin is not liquid enough against the bridge, empty if it is.
+//
+// If the stats are unknown, the coin is considered liquid to not block jumps
+func (c *Client) CheckCoinLiquidity(ctx context.Context, coin string) string {
+	stats, ok := c.GetTickerStats(ctx)[util.Symbol(coin, c.ConfigFile.Bridge)]
+	if !ok {
+		return ""
+	}
+	return stats.CheckLiquidity(c.ConfigFile.Liquidity.MinQuoteVolume, c.ConfigFile.Liquidity.MaxSpread)
+}
+
+// Fetch the stats of all symbols, it's heavier than asking a few symbols but also used to rank the whole market
+func (c *Client) RefreshTickerStats(ctx context.Context) (map[string]TickerStats, error) {
+	stats, err := c.client.NewListPriceChangeStatsService().Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get 24h ticker stats: %w", err)
+	}
+
+	res := make(map[string]TickerStats, len(stats))
+	for _, s := range stats {
+		res[s.Symbol] = TickerStats{
+			Symbol:      s.Symbol,
+			QuoteVolume: decimalOrZero(s.QuoteVolume),
+			PriceChange: decimalOrZero(s.PriceChangePercent),
+			HighPrice:   decimalOrZero(s.HighPrice),
+			LowPrice:    decimalOrZero(s.LowPrice),
+			LastPrice:   decimalOrZero(s.LastPrice),
+			BidPrice:    decimalOrZero(s.BidPrice),
+			AskPrice:    decimalOrZero(s.AskPrice),
+			NbTrades:    s.Count,
+		}
+	}
+	return res, nil
+}
+
+// Stats are informative, a malformed value is just considered unknown
+func decimalOrZero(value string) decimal.Decimal {
+	d, err := decimal.NewFromString(value)
+	if err != nil {
+		return decimal.Zero
+	}
+	return d
+}
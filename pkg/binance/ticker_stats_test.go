@@ -0,0 +1,27 @@
+package binance_test
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/erwanlbp/trading-bot/pkg/binance"
+)
+
+func TestTickerStatsCheckLiquidity(t *testing.T) {
+	t.Parallel()
+
+	stats := binance.TickerStats{
+		QuoteVolume: decimal.NewFromInt(500_000),
+		BidPrice:    decimal.NewFromFloat(99.9),
+		AskPrice:    decimal.NewFromFloat(100.1),
+	}
+
+	assert.Equal(t, "0.200", stats.Spread().StringFixed(3))
+
+	assert.Empty(t, stats.CheckLiquidity(decimal.Zero, decimal.Zero), "no limit")
+	assert.Empty(t, stats.CheckLiquidity(decimal.NewFromInt(100_000), decimal.NewFromFloat(0.5)))
+	assert.NotEmpty(t, stats.CheckLiquidity(decimal.NewFromInt(1_000_000), decimal.Zero), "volume too low")
+	assert.NotEmpty(t, stats.CheckLiquidity(decimal.Zero, decimal.NewFromFloat(0.1)), "spread too wide")
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/shopspring/decimal"
 	"go.uber.org/zap"
 
+	"github.com/erwanlbp/trading-bot/pkg/eventbus"
 	"github.com/erwanlbp/trading-bot/pkg/util"
 )
 
@@ -38,8 +39,17 @@ func (c *Client) IsTradeInProgress() bool {
 	return c.tradeInProgress.Load()
 }
 
+// Sell only a part (ratio between 0 and 1) of the coin balance
+func (c *Client) SellPart(ctx context.Context, coin, stableCoin string, ratio decimal.Decimal) (OrderResult, error) {
+	return c.trade(ctx, coin, stableCoin, binance.SideTypeSell, ratio)
+}
+
 // Do not call this one directly, use .Buy() or .Sell()
 func (c *Client) Trade(ctx context.Context, coin, stableCoin string, side binance.SideType) (OrderResult, error) {
+	return c.trade(ctx, coin, stableCoin, side, decimal.NewFromInt(1))
+}
+
+func (c *Client) trade(ctx context.Context, coin, stableCoin string, side binance.SideType, ratio decimal.Decimal) (OrderResult, error) {
 	logger := c.Logger.With(zap.Any("trade", side))
 
 	balances, err := c.GetBalance(ctx)
@@ -51,9 +61,18 @@ func (c *Client) Trade(ctx context.Context, coin, stableCoin string, side binanc
 	var balance decimal.Decimal
 	if side == binance.SideTypeBuy {
 		balance = balances[stableCoin]
+		if stableCoin == c.ConfigFile.Bridge {
+			reserve, err := c.BridgeReserve.CapBridgeReserve(balance)
+			if err != nil {
+				logger.Error("Failed to get bridge reserve", zap.Error(err))
+				return OrderResult{}, err
+			}
+			balance = balance.Sub(reserve)
+		}
 	} else {
 		balance = balances[coin]
 	}
+	balance = balance.Mul(ratio)
 
 	symbol := util.Symbol(coin, stableCoin)
 
@@ -98,6 +117,7 @@ func (c *Client) Trade(ctx context.Context, coin, stableCoin string, side binanc
 	if err != nil {
 		return OrderResult{}, fmt.Errorf("failed to create order: %w", err)
 	}
+	eventbus.Publish(c.EventBus, OrderPlaced, createdOrderEvent(res))
 
 	order, err := c.WaitForOrderCompletion(ctx, symbol, res.OrderID)
 	if err != nil {
@@ -130,6 +150,7 @@ func (c *Client) WaitForOrderCompletion(ctx context.Context, symbol string, orde
 				c.Logger.Error("Failed to cancel order", zap.Error(err))
 				return OrderResult{Order: orderLastStatus, Cancel: cancelStatus}, err
 			}
+			eventbus.Publish(c.EventBus, OrderCancelled, cancelledOrderEvent(cancelStatus, "bot is stopping"))
 
 			c.Logger.Info(fmt.Sprintf("Canceled order '%d' because bot is stopping", orderId))
 
@@ -141,6 +162,7 @@ func (c *Client) WaitForOrderCompletion(ctx context.Context, symbol string, orde
 				c.Logger.Error("Failed to cancel order", zap.Error(err))
 				return OrderResult{Order: orderLastStatus, Cancel: cancelStatus}, err
 			}
+			eventbus.Publish(c.EventBus, OrderCancelled, cancelledOrderEvent(cancelStatus, "wait timeout reached"))
 			return OrderResult{Order: orderLastStatus, Cancel: cancelStatus}, fmt.Errorf("wait timeout reached")
 		case <-ticker.C:
 			order, err := c.client.NewGetOrderService().Symbol(symbol).OrderID(orderId).Do(ctx)
@@ -148,6 +170,9 @@ func (c *Client) WaitForOrderCompletion(ctx context.Context, symbol string, orde
 				c.Logger.Error("Error while waiting for order completion, will continue to wait (and retry) until timeout", zap.Error(err))
 				continue
 			}
+			if orderLastStatus == nil || orderLastStatus.Status != order.Status || orderLastStatus.ExecutedQuantity != order.ExecutedQuantity {
+				eventbus.Publish(c.EventBus, OrderUpdated, orderEvent(order))
+			}
 			orderLastStatus = order
 			switch order.Status {
 			case binance.OrderStatusTypeNew:
@@ -156,17 +181,21 @@ func (c *Client) WaitForOrderCompletion(ctx context.Context, symbol string, orde
 				c.Logger.Debug(fmt.Sprintf("Order '%d' is partially filled (%s/%s)", order.OrderID, order.ExecutedQuantity, order.OrigQuantity))
 			case binance.OrderStatusTypeFilled:
 				c.Logger.Debug(fmt.Sprintf("Order '%d' is filled", order.OrderID))
+				eventbus.Publish(c.EventBus, OrderFilled, orderEvent(order))
 				return OrderResult{Order: orderLastStatus}, nil
 			case binance.OrderStatusTypeRejected:
 				c.Logger.Error(fmt.Sprintf("Order '%d' got rejected", order.OrderID))
+				eventbus.Publish(c.EventBus, OrderCancelled, withReason(orderEvent(order), "rejected"))
 				return OrderResult{Order: orderLastStatus}, fmt.Errorf("order got rejected")
 			case binance.OrderStatusTypePendingCancel:
 				c.Logger.Debug(fmt.Sprintf("Order '%d' is pending cancel", order.OrderID))
 			case binance.OrderStatusTypeCanceled:
 				c.Logger.Error(fmt.Sprintf("Order '%d' is canceled", order.OrderID))
+				eventbus.Publish(c.EventBus, OrderCancelled, withReason(orderEvent(order), "canceled on the exchange"))
 				return OrderResult{Order: orderLastStatus}, fmt.Errorf("order got canceled")
 			case binance.OrderStatusTypeExpired:
 				c.Logger.Warn(fmt.Sprintf("Order '%d' is expired", order.OrderID))
+				eventbus.Publish(c.EventBus, OrderCancelled, withReason(orderEvent(order), "expired"))
 				return OrderResult{Order: orderLastStatus}, fmt.Errorf("order is expired")
 			default:
 				c.Logger.Warn(fmt.Sprintf("Unknown status '%s' while waiting for order completion, will continue to wait", order.Status))
@@ -195,6 +224,25 @@ func (r OrderResult) Price() decimal.Decimal {
 	return decimal.Zero
 }
 
+// Average price of the executed quantity, falls back on the order price if nothing is executed
+func (r OrderResult) AvgPrice() decimal.Decimal {
+	var quote, quantity string
+	if r.Cancel != nil {
+		quote, quantity = r.Cancel.CummulativeQuoteQuantity, r.Cancel.ExecutedQuantity
+	} else if r.Order != nil {
+		quote, quantity = r.Order.CummulativeQuoteQuantity, r.Order.ExecutedQuantity
+	}
+	q, err := decimal.NewFromString(quote)
+	if err != nil {
+		return r.Price()
+	}
+	qty, err := decimal.NewFromString(quantity)
+	if err != nil || qty.IsZero() {
+		return r.Price()
+	}
+	return q.Div(qty)
+}
+
 func (r OrderResult) Quantity() decimal.Decimal {
 	if r.Cancel != nil {
 		return decimal.RequireFromString(r.Cancel.ExecutedQuantity)
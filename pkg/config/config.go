@@ -10,7 +10,8 @@ import (
 	"github.com/erwanlbp/trading-bot/pkg/util"
 )
 
-func LoadCoins(enabledCoins []string, logger *log.Logger, repo *repository.Repository) error {
+// Coins in trackedCoins are saved disabled (if not enabled), only to fetch their prices
+func LoadCoins(enabledCoins, trackedCoins []string, logger *log.Logger, repo *repository.Repository) error {
 
 	logger.Info(fmt.Sprintf("Found %d supported coins in config file", len(enabledCoins)))
 
@@ -38,6 +39,11 @@ func LoadCoins(enabledCoins []string, logger *log.Logger, repo *repository.Repos
 			existingCoinsMap[c] = coin
 		}
 	}
+	for _, coin := range trackedCoins {
+		if _, alreadyCreated := existingCoinsMap[coin]; !alreadyCreated {
+			existingCoinsMap[coin] = model.Coin{Coin: coin, Enabled: false, EnabledOn: now}
+		}
+	}
 	if len(existingCoinsMap) == 0 {
 		return nil
 	}
@@ -96,3 +96,64 @@ func TestGetNeededGain(t *testing.T) {
 		})
 	}
 }
+
+func TestVirtualStrategies(t *testing.T) {
+	t.Parallel()
+
+	cf := configfile.ConfigFile{
+		Coins:  []string{"AVAX", "SOL"},
+		Shadow: configfile.VirtualStrategy{Enabled: true, Coins: []string{"AVAX", "DOT"}},
+		Strategies: []configfile.VirtualStrategy{
+			{Name: "a", Enabled: true, Coins: []string{"NEAR", "DOT"}},
+			{Name: "b", Enabled: false, Coins: []string{"ATOM"}},
+		},
+	}
+
+	names := []string{}
+	for _, s := range cf.VirtualStrategies() {
+		names = append(names, s.Name)
+	}
+	assert.Equal(t, []string{configfile.ShadowStrategyName, "a"}, names)
+	assert.ElementsMatch(t, []string{"DOT", "NEAR"}, cf.VirtualOnlyCoins())
+	assert.Equal(t, []string{"AVAX", "SOL"}, cf.StrategyCoins(configfile.VirtualStrategy{}))
+	assert.NoError(t, cf.ValidateStrategies())
+
+	cf.Strategies[1].Name = "a"
+	assert.Error(t, cf.ValidateStrategies())
+	cf.Strategies[1].Name = configfile.ShadowStrategyName
+	assert.Error(t, cf.ValidateStrategies())
+}
+
+func TestTradingWindows(t *testing.T) {
+	t.Parallel()
+
+	w := configfile.TradingWindows{
+		Weekdays: []string{"mon", "fri"},
+		Hours:    []string{"08:00-12:00", "22:00-02:00"},
+	}
+	assert.NoError(t, w.Validate())
+
+	// 2024-05-06 is a monday
+	at := func(day, hour, minute int) time.Time { return time.Date(2024, 5, day, hour, minute, 0, 0, time.UTC) }
+
+	assert.True(t, w.IsOpen(at(6, 8, 0)))
+	assert.False(t, w.IsOpen(at(6, 12, 0)))
+	assert.True(t, w.IsOpen(at(6, 23, 0)))
+	assert.True(t, w.IsOpen(at(7, 1, 59)), "range started on monday")
+	assert.False(t, w.IsOpen(at(7, 8, 30)), "tuesday")
+	assert.False(t, w.IsOpen(at(6, 1, 0)), "range started on sunday")
+
+	assert.True(t, configfile.TradingWindows{}.IsOpen(at(7, 3, 0)))
+	assert.Error(t, (&configfile.TradingWindows{Hours: []string{"8h-12h"}}).Validate())
+	assert.Error(t, (&configfile.TradingWindows{Weekdays: []string{"monday"}}).Validate())
+	assert.Error(t, (&configfile.TradingWindows{Timezone: "Nowhere/City"}).Validate())
+}
+
+func TestJumpValidate(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, configfile.Jump{MaxHops: 1}.Validate())
+	assert.NoError(t, configfile.Jump{MaxHops: configfile.MaxJumpHops}.Validate())
+	assert.Error(t, configfile.Jump{MaxHops: 0}.Validate())
+	assert.Error(t, configfile.Jump{MaxHops: configfile.MaxJumpHops + 1}.Validate())
+}
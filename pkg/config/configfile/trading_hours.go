@@ -0,0 +1,145 @@
+package configfile
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// When jumps are allowed, everything is allowed if empty
+type TradingWindows struct {
+	// IANA name, UTC if not provided
+	Timezone string `yaml:"timezone,omitempty"`
+	// mon, tue, wed, thu, fri, sat, sun
+	Weekdays []string `yaml:"weekdays,omitempty"`
+	// 15:04-15:04 ranges, can go over midnight like 22:00-02:00
+	Hours []string `yaml:"hours,omitempty"`
+
+	// Set by Validate, IsOpen is checked on every tick
+	parsed *parsedTradingWindows
+}
+
+type parsedTradingWindows struct {
+	location *time.Location
+	// Minutes of the day
+	ranges [][2]int
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+func (w TradingWindows) Location() (*time.Location, error) {
+	if w.parsed != nil {
+		return w.parsed.location, nil
+	}
+	if w.Timezone == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(w.Timezone)
+}
+
+// Also loads the timezone and parses the ranges once
+func (w *TradingWindows) Validate() error {
+	parsed, err := w.parse()
+	if err != nil {
+		return err
+	}
+	w.parsed = parsed
+	return nil
+}
+
+func (w TradingWindows) parse() (*parsedTradingWindows, error) {
+	var res parsedTradingWindows
+	var err error
+	if res.location, err = w.Location(); err != nil {
+		return nil, fmt.Errorf("invalid timezone '%s': %w", w.Timezone, err)
+	}
+	for _, day := range w.Weekdays {
+		if _, ok := weekdays[strings.ToLower(day)]; !ok {
+			return nil, fmt.Errorf("invalid weekday '%s'", day)
+		}
+	}
+	for _, hours := range w.Hours {
+		from, to, err := parseHoursRange(hours)
+		if err != nil {
+			return nil, err
+		}
+		res.ranges = append(res.ranges, [2]int{from, to})
+	}
+	return &res, nil
+}
+
+// Must be validated before, otherwise it's parsed on each call
+func (w TradingWindows) IsOpen(t time.Time) bool {
+	parsed := w.parsed
+	if parsed == nil {
+		var err error
+		if parsed, err = w.parse(); err != nil {
+			return false
+		}
+	}
+	t = t.In(parsed.location)
+
+	minutes := t.Hour()*60 + t.Minute()
+	day := t.Weekday()
+
+	if len(parsed.ranges) == 0 {
+		return w.isOpenDay(day)
+	}
+	for _, r := range parsed.ranges {
+		from, to := r[0], r[1]
+		if from <= to {
+			if minutes >= from && minutes < to && w.isOpenDay(day) {
+				return true
+			}
+			continue
+		}
+		// Over midnight, the range belongs to the day it starts
+		if minutes >= from && w.isOpenDay(day) {
+			return true
+		}
+		if minutes < to && w.isOpenDay((day+6)%7) {
+			return true
+		}
+	}
+	return false
+}
+
+func (w TradingWindows) isOpenDay(day time.Weekday) bool {
+	if len(w.Weekdays) == 0 {
+		return true
+	}
+	for _, d := range w.Weekdays {
+		if weekdays[strings.ToLower(d)] == day {
+			return true
+		}
+	}
+	return false
+}
+
+// Return the range as minutes of the day
+func parseHoursRange(hours string) (int, int, error) {
+	parts := strings.Split(hours, "-")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("invalid hours range '%s', expected 15:04-15:04", hours)
+	}
+	var res [2]int
+	for i, part := range parts {
+		t, err := time.Parse("15:04", strings.TrimSpace(part))
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid hours range '%s': %w", hours, err)
+		}
+		res[i] = t.Hour()*60 + t.Minute()
+	}
+	if res[0] == res[1] {
+		return 0, 0, fmt.Errorf("invalid hours range '%s', empty", hours)
+	}
+	return res[0], res[1], nil
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -14,9 +15,11 @@ import (
 	"github.com/erwanlbp/trading-bot/pkg/config/globalconf"
 	"github.com/erwanlbp/trading-bot/pkg/constant"
 	"github.com/erwanlbp/trading-bot/pkg/db"
+	"github.com/erwanlbp/trading-bot/pkg/db/postgres"
 	"github.com/erwanlbp/trading-bot/pkg/db/sqlite"
 	"github.com/erwanlbp/trading-bot/pkg/eventbus"
 	"github.com/erwanlbp/trading-bot/pkg/log"
+	"github.com/erwanlbp/trading-bot/pkg/model"
 	"github.com/erwanlbp/trading-bot/pkg/process"
 	"github.com/erwanlbp/trading-bot/pkg/repository"
 	"github.com/erwanlbp/trading-bot/pkg/service"
@@ -40,18 +43,37 @@ type Config struct {
 	TelegramClient *telegram.Client
 
 	ProcessPriceGetter       *process.PriceGetter
+	ProcessPriceValidator    *process.PriceValidator
 	ProcessJumpFinder        *process.JumpFinder
+	ProcessVirtualTrader     *process.VirtualTrader
+	ProcessTakeProfiter      *process.TakeProfiter
+	ProcessCircuitBreaker    *process.CircuitBreaker
+	ProcessPauseWatcher      *process.PauseWatcher
+	ProcessUniverseManager   *process.UniverseManager
+	ProcessLosingCoinGuard   *process.LosingCoinGuard
 	ProcessFeeGetter         *process.FeeGetter
 	ProcessCleaner           *process.Cleaner
 	TelegramHandlers         *handlers.Handlers
 	ProcessTelegramNotifier  *process.TelegramNotifier
 	ProcessSymbolBlacklister *process.SymbolBlacklister
+	ProcessEventJournal      *process.EventJournal
+	ProcessBackuper          *process.Backuper
+	ProcessArchiver          *process.Archiver
 	BalanceSaver             *process.BalanceSaver
 }
 
 var _ globalconf.GlobalConfModifier = &Config{}
 
 func Init(ctx context.Context) *Config {
+	return initConfig(ctx, true)
+}
+
+// Same as Init without the telegram bot, nothing is sent to the channel and logs only go to the console. For tools that must not look like the live bot
+func InitOffline(ctx context.Context) *Config {
+	return initConfig(ctx, false)
+}
+
+func initConfig(ctx context.Context, withTelegram bool) *Config {
 
 	var conf Config
 
@@ -65,33 +87,44 @@ func Init(ctx context.Context) *Config {
 	}
 	conf.ConfigFile = &cf
 
-	telebot, err := telegram.NewClient(ctx, simpleLogger, conf.ConfigFile)
-	if err != nil {
-		simpleLogger.Warn("Failed to init telegram bot (trading-bot still running)", zap.Error(err))
-	}
-	conf.TelegramClient = telebot
+	conf.Logger = simpleLogger
+	if withTelegram {
+		telebot, err := telegram.NewClient(ctx, simpleLogger, conf.ConfigFile)
+		if err != nil {
+			simpleLogger.Warn("Failed to init telegram bot (trading-bot still running)", zap.Error(err))
+		}
+		conf.TelegramClient = telebot
 
-	conf.Logger = log.NewZapLogger(telegram.ZapCoreWrapper(conf.TelegramClient, conf.ConfigFile))
+		conf.Logger = log.NewZapLogger(telegram.ZapCoreWrapper(conf.TelegramClient, conf.ConfigFile))
+	}
 
-	dbFilePath := getDBFilePath(conf.ConfigFile.TestMode)
-	sqliteDb, err := sqlite.NewDB(conf.Logger, dbFilePath)
+	conf.DB, err = OpenDB(conf.Logger, conf.ConfigFile)
 	if err != nil {
 		conf.Logger.Fatal("Failed to initialize DB", zap.Error(err))
 	}
-	conf.DB = db.NewDB(sqliteDb)
 
 	conf.Repository = repository.NewRepository(conf.DB, conf.ConfigFile, conf.Logger)
 
 	conf.ProcessSymbolBlacklister = process.NewSymbolBlacklister(conf.Logger, conf.EventBus, conf.Repository)
 
-	conf.BinanceClient = binance.NewClient(conf.Logger, conf.ConfigFile, conf.EventBus, conf.ProcessSymbolBlacklister)
+	conf.BinanceClient = binance.NewClient(conf.Logger, conf.ConfigFile, conf.EventBus, conf.ProcessSymbolBlacklister, conf.Repository)
 
 	conf.Service = service.NewService(conf.Logger, conf.Repository, conf.BinanceClient, conf.ConfigFile)
 
 	conf.ProcessPriceGetter = process.NewPriceGetter(conf.Logger, conf.BinanceClient, conf.Repository, conf.EventBus, constant.AltCoins)
-	conf.ProcessJumpFinder = process.NewJumpFinder(conf.Logger, conf.Repository, conf.EventBus, conf.ConfigFile, conf.BinanceClient)
+	conf.ProcessPauseWatcher = process.NewPauseWatcher(conf.Logger, conf.Repository, conf.ConfigFile)
+	conf.ProcessPriceValidator = process.NewPriceValidator(conf.Logger, conf.Repository, conf.EventBus, conf.ConfigFile)
+	conf.ProcessCircuitBreaker = process.NewCircuitBreaker(conf.Logger, conf.Repository, conf.EventBus, conf.ConfigFile, conf.BinanceClient)
+	conf.ProcessJumpFinder = process.NewJumpFinder(conf.Logger, conf.Repository, conf.EventBus, conf.ConfigFile, conf.BinanceClient, conf.ProcessCircuitBreaker, conf.TelegramClient)
+	conf.ProcessVirtualTrader = process.NewVirtualTrader(conf.Logger, conf.Repository, conf.EventBus, conf.ConfigFile, conf.BinanceClient, conf.ProcessJumpFinder)
+	conf.ProcessTakeProfiter = process.NewTakeProfiter(conf.Logger, conf.Repository, conf.EventBus, conf.ConfigFile, conf.BinanceClient)
 	conf.ProcessFeeGetter = process.NewFeeGetter(conf.Logger, conf.BinanceClient)
-	conf.ProcessCleaner = process.NewCleaner(conf.Logger, conf.Repository, &conf)
+	conf.ProcessCleaner = process.NewCleaner(conf.Logger, conf.Repository, conf.ConfigFile, &conf)
+	conf.ProcessBackuper = process.NewBackuper(conf.Logger, conf.DB, conf.ConfigFile)
+	conf.ProcessArchiver = process.NewArchiver(conf.Logger, conf.Repository, conf.ConfigFile)
+	conf.ProcessLosingCoinGuard = process.NewLosingCoinGuard(conf.Logger, conf.Repository, conf.ConfigFile, conf.Service)
+	conf.ProcessUniverseManager = process.NewUniverseManager(conf.Logger, conf.Repository, conf.ConfigFile, conf.Service, &conf)
+	conf.ProcessEventJournal = process.NewEventJournal(conf.Logger, conf.EventBus, conf.ConfigFile)
 	conf.ProcessTelegramNotifier = process.NewTelegramNotifier(conf.Logger, conf.EventBus, conf.TelegramClient)
 	conf.TelegramHandlers = handlers.NewHandlers(conf.Logger, conf.ConfigFile, conf.TelegramClient, conf.BinanceClient, conf.Repository, &conf)
 	conf.BalanceSaver = process.NewBalanceSaver(conf.Logger, conf.Repository, conf.EventBus, conf.BinanceClient)
@@ -99,6 +132,39 @@ func Init(ctx context.Context) *Config {
 	return &conf
 }
 
+// SQLite file used instead of the DB of the config when set, for tools that must not write in the bot's DB
+const DBPathEnv = "DB_PATH"
+
+// Open the DB of the config, without migrating it. Postgres if a DSN is configured, SQLite file otherwise
+func OpenDB(logger *log.Logger, cf *configfile.ConfigFile) (*db.DB, error) {
+	if path, ok := os.LookupEnv(DBPathEnv); ok {
+		sqliteDb, err := sqlite.NewDB(logger, path)
+		if err != nil {
+			return nil, err
+		}
+		return db.NewDB(sqliteDb), nil
+	}
+
+	if cf.Database.IsPostgres() {
+		postgresDb, err := postgres.NewDB(logger, cf.Database.DSN)
+		if err != nil {
+			return nil, err
+		}
+		return db.NewDB(postgresDb), nil
+	}
+
+	sqliteDb, err := sqlite.NewDB(logger, getDBFilePath(cf.TestMode))
+	if err != nil {
+		return nil, err
+	}
+	return db.NewDB(sqliteDb), nil
+}
+
+// Path of the SQLite DB file, not used with postgres
+func DBFilePath(cf *configfile.ConfigFile) string {
+	return getDBFilePath(cf.TestMode)
+}
+
 func getDBFilePath(testMode bool) string {
 	filepath := "data/trading_bot.db"
 	if testMode {
@@ -126,7 +192,7 @@ func (c *Config) ReloadConfigFile(ctx context.Context) error {
 	}
 
 	logger.Debug("Reloading supported coins")
-	if err := LoadCoins(newConfig.Coins, logger, c.Repository); err != nil {
+	if err := LoadCoins(newConfig.Coins, newConfig.VirtualOnlyCoins(), logger, c.Repository); err != nil {
 		return fmt.Errorf("failed to reload supported coins: %w", err)
 	}
 
@@ -141,24 +207,77 @@ func (c *Config) ReloadConfigFile(ctx context.Context) error {
 	return nil
 }
 
-func (c *Config) ExportDBFile() (io.Reader, error) {
+// Export the whole DB, return its content and a file name
+func (c *Config) ExportDBFile(ctx context.Context) (io.Reader, string, error) {
 	if err := c.Repository.Vacuum(); err != nil {
-		return nil, fmt.Errorf("failed to vacuum before export: %w", err)
+		return nil, "", fmt.Errorf("failed to vacuum before export: %w", err)
+	}
+
+	date := time.Now().Format(time.DateOnly)
+
+	if c.DB.Dialect() == db.DialectPostgres {
+		content, err := postgres.Dump(ctx, c.ConfigFile.Database.DSN)
+		if err != nil {
+			return nil, "", err
+		}
+		return content, fmt.Sprintf("db_%s.dump", date), nil
 	}
 
 	content, err := os.ReadFile(getDBFilePath(c.ConfigFile.TestMode))
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	b := bytes.NewBuffer(content)
-	return b, nil
+	return b, fmt.Sprintf("db_%s.db", date), nil
 }
 
 func (c *Config) GetDBSize() (int64, error) {
+	if c.DB.Dialect() == db.DialectPostgres {
+		return postgres.Size(c.DB.DB)
+	}
+
 	stat, err := os.Stat(getDBFilePath(c.ConfigFile.TestMode))
 	if err != nil {
 		return 0, err
 	}
 	return stat.Size(), nil
 }
+
+func (c *Config) ManualJumpTo(ctx context.Context, coin string) error {
+	return c.ProcessJumpFinder.ManualJumpTo(ctx, coin)
+}
+
+func (c *Config) ManualExitToBridge(ctx context.Context) error {
+	return c.ProcessJumpFinder.ManualExitToBridge(ctx)
+}
+
+func (c *Config) ManualEnter(ctx context.Context, coin string) error {
+	return c.ProcessJumpFinder.ManualEnter(ctx, coin)
+}
+
+func (c *Config) ProposeUniverse(ctx context.Context) (service.UniverseProposal, error) {
+	return c.Service.ProposeUniverse(ctx)
+}
+
+func (c *Config) Backfill(ctx context.Context, req service.BackfillRequest) (service.BackfillResult, error) {
+	return c.Service.Backfill(ctx, req)
+}
+
+func (c *Config) GetLastHoldings(n int) (map[string][]model.Holding, error) {
+	return c.Service.GetLastHoldings(n)
+}
+
+func (c *Config) ApplyCoins(ctx context.Context, coins []string) error {
+	if err := configfile.CopyFileToBackup(); err != nil {
+		return fmt.Errorf("failed to backup the config file: %w", err)
+	}
+
+	newConf := *c.ConfigFile
+	newConf.Coins = coins
+	if err := newConf.SaveToFile(); err != nil {
+		return fmt.Errorf("failed to save the config file: %w", err)
+	}
+
+	return c.ReloadConfigFile(ctx)
+}
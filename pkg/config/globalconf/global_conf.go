@@ -3,11 +3,29 @@ package globalconf
 import (
 	"context"
 	"io"
+
+	"github.com/erwanlbp/trading-bot/pkg/model"
+	"github.com/erwanlbp/trading-bot/pkg/service"
 )
 
 // To inject functions that can act on global config/dependancies object
 type GlobalConfModifier interface {
 	ReloadConfigFile(context.Context) error
-	ExportDBFile() (io.Reader, error)
+	// Return the content and a file name
+	ExportDBFile(context.Context) (io.Reader, string, error)
 	GetDBSize() (int64, error)
+
+	ManualJumpTo(ctx context.Context, coin string) error
+	ManualExitToBridge(context.Context) error
+	ManualEnter(ctx context.Context, coin string) error
+
+	ProposeUniverse(context.Context) (service.UniverseProposal, error)
+	// Save the coin list in the config file and reload it
+	ApplyCoins(ctx context.Context, coins []string) error
+
+	// Download klines of the coins into the price and ratio history
+	Backfill(context.Context, service.BackfillRequest) (service.BackfillResult, error)
+
+	// Last completed holdings of each coin, with the result of the other coins over the same time
+	GetLastHoldings(n int) (map[string][]model.Holding, error)
 }
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/erwanlbp/trading-bot/pkg/config/configfile"
+)
+
+// Written next to the DB file while the bot runs, so tools replacing the DB can check it's stopped
+func pidFilePath(cf *configfile.ConfigFile) string {
+	return getDBFilePath(cf.TestMode) + ".pid"
+}
+
+func WritePIDFile(cf *configfile.ConfigFile) error {
+	return os.WriteFile(pidFilePath(cf), []byte(strconv.Itoa(os.Getpid())), 0644)
+}
+
+func RemovePIDFile(cf *configfile.ConfigFile) {
+	_ = os.Remove(pidFilePath(cf))
+}
+
+// PID of the bot using the DB, if it's running. A PID file left by a crash is ignored
+func RunningBotPID(cf *configfile.ConfigFile) (int, bool) {
+	content, err := os.ReadFile(pidFilePath(cf))
+	if err != nil {
+		return 0, false
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(content)))
+	if err != nil {
+		return 0, false
+	}
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return 0, false
+	}
+	if err := process.Signal(syscall.Signal(0)); err != nil {
+		return 0, false
+	}
+	return pid, true
+}
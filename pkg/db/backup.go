@@ -0,0 +1,156 @@
+package db
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	BackupDaily  = "daily"
+	BackupWeekly = "weekly"
+
+	backupTimeFormat = "2006-01-02T150405"
+)
+
+// A backup file, named <kind>-<time>.<ext>
+type BackupFile struct {
+	Path string
+	Kind string
+	Time time.Time
+	Size int64
+}
+
+func (b BackupFile) IsGzipped() bool {
+	return strings.HasSuffix(b.Path, ".gz")
+}
+
+func BackupFileName(kind string, t time.Time, ext string) string {
+	return fmt.Sprintf("%s-%s.%s", kind, t.UTC().Format(backupTimeFormat), ext)
+}
+
+// Consistent copy of the SQLite DB into a new file, while it's in use
+func (d *DB) VacuumInto(path string) error {
+	if d.Dialect() != DialectSQLite {
+		return fmt.Errorf("VACUUM INTO is not supported by %s", d.Dialect())
+	}
+	return d.DB.Exec("VACUUM INTO ?", path).Error
+}
+
+// Backups of the dir, oldest first. Other files are ignored
+func ListBackups(dir string) ([]BackupFile, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var res []BackupFile
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		kind, rest, found := strings.Cut(entry.Name(), "-")
+		if !found || (kind != BackupDaily && kind != BackupWeekly) {
+			continue
+		}
+		ts, _, _ := strings.Cut(rest, ".")
+		t, err := time.Parse(backupTimeFormat, ts)
+		if err != nil {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, BackupFile{Path: filepath.Join(dir, entry.Name()), Kind: kind, Time: t, Size: info.Size()})
+	}
+
+	sort.SliceStable(res, func(i, j int) bool { return res[i].Time.Before(res[j].Time) })
+
+	return res, nil
+}
+
+// Keep the last backups of each kind, return the removed ones
+func RotateBackups(dir string, keepDaily, keepWeekly int) ([]BackupFile, error) {
+	backups, err := ListBackups(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	keep := map[string]int{BackupDaily: keepDaily, BackupWeekly: keepWeekly}
+	var removed []BackupFile
+	// Newest first
+	for i := len(backups) - 1; i >= 0; i-- {
+		b := backups[i]
+		if keep[b.Kind] > 0 {
+			keep[b.Kind]--
+			continue
+		}
+		if err := os.Remove(b.Path); err != nil {
+			return removed, fmt.Errorf("failed removing %s: %w", b.Path, err)
+		}
+		removed = append(removed, b)
+	}
+	return removed, nil
+}
+
+func CopyFile(src, dst string) error {
+	return transformFile(src, dst, func(w io.Writer, r io.Reader) error {
+		_, err := io.Copy(w, r)
+		return err
+	})
+}
+
+func GzipFile(src, dst string) error {
+	return transformFile(src, dst, func(w io.Writer, r io.Reader) error {
+		gz := gzip.NewWriter(w)
+		if _, err := io.Copy(gz, r); err != nil {
+			return err
+		}
+		return gz.Close()
+	})
+}
+
+func GunzipFile(src, dst string) error {
+	return transformFile(src, dst, func(w io.Writer, r io.Reader) error {
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		_, err = io.Copy(w, gz)
+		return err
+	})
+}
+
+// Write dst from src, dst is removed if anything fails
+func transformFile(src, dst string, transform func(io.Writer, io.Reader) error) (err error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := out.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			os.Remove(dst)
+		}
+	}()
+
+	return transform(out, in)
+}
@@ -0,0 +1,42 @@
+package db_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/erwanlbp/trading-bot/pkg/db"
+)
+
+func TestRotateBackups(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	start := time.Date(2024, 5, 1, 3, 30, 0, 0, time.UTC)
+	for day := 0; day < 10; day++ {
+		ts := start.Add(time.Duration(day) * 24 * time.Hour)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, db.BackupFileName(db.BackupDaily, ts, "db.gz")), nil, 0644))
+		if day%7 == 0 {
+			require.NoError(t, os.WriteFile(filepath.Join(dir, db.BackupFileName(db.BackupWeekly, ts, "db.gz")), nil, 0644))
+		}
+	}
+	// Not a backup
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), nil, 0644))
+
+	removed, err := db.RotateBackups(dir, 3, 1)
+	require.NoError(t, err)
+	assert.Len(t, removed, 8)
+
+	backups, err := db.ListBackups(dir)
+	require.NoError(t, err)
+	var kept []string
+	for _, b := range backups {
+		kept = append(kept, b.Kind+" "+b.Time.Format(time.DateOnly))
+	}
+	assert.Equal(t, []string{"daily 2024-05-08", "weekly 2024-05-08", "daily 2024-05-09", "daily 2024-05-10"}, kept)
+	assert.FileExists(t, filepath.Join(dir, "notes.txt"))
+}
@@ -1,9 +1,9 @@
 package db
 
 import (
-	"gorm.io/gorm"
+	"fmt"
 
-	"github.com/erwanlbp/trading-bot/pkg/model"
+	"gorm.io/gorm"
 )
 
 type DB struct {
@@ -16,17 +16,27 @@ func NewDB(db *gorm.DB) *DB {
 	}
 }
 
+func (d *DB) SchemaMigrator() (*Migrator, error) {
+	return NewMigrator(d.DB, Migrations)
+}
+
+// Apply all the pending migrations. Fails if the DB has migrations this version of the bot doesn't know, it's been used by a newer one
 func (d *DB) MigrateSchema() error {
-	return d.DB.AutoMigrate(
-		model.Coin{},
-		model.CoinPrice{},
-		model.CurrentCoin{},
-		model.Pair{},
-		model.PairHistory{},
-		model.Jump{},
-		model.Diff{},
-		model.Chart{},
-		model.BlacklistedSymbol{},
-		model.BalanceHistory{},
-	)
+	migrator, err := d.SchemaMigrator()
+	if err != nil {
+		return err
+	}
+
+	status, err := migrator.Status()
+	if err != nil {
+		return err
+	}
+	for _, s := range status {
+		if s.Unknown {
+			return fmt.Errorf("DB has unknown migration %d '%s', it's newer than the bot (latest known is %d)", s.Version, s.Name, migrator.LatestVersion())
+		}
+	}
+
+	_, err = migrator.Up(0)
+	return err
 }
@@ -0,0 +1,10 @@
+package db
+
+const (
+	DialectSQLite   = "sqlite"
+	DialectPostgres = "postgres"
+)
+
+func (d *DB) Dialect() string {
+	return d.DB.Dialector.Name()
+}
@@ -0,0 +1,218 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/erwanlbp/trading-bot/pkg/model"
+)
+
+// A schema change, run in a transaction with its record in schema_migrations
+type Migration struct {
+	// Migrations are applied by increasing version, versions of applied migrations must never change
+	Version uint
+	Name    string
+	Up      func(tx *gorm.DB) error
+	// Nil if the migration can't be reverted
+	Down func(tx *gorm.DB) error
+	// On a DB created before migrations existed, return true if it already has the changes of the migration, it's then recorded without running Up
+	Detect func(tx *gorm.DB) bool
+}
+
+type MigrationStatus struct {
+	Version   uint
+	Name      string
+	Applied   bool
+	AppliedOn time.Time
+	Detected  bool
+	// Applied on the DB but unknown to this version of the bot
+	Unknown bool
+}
+
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+func NewMigrator(db *gorm.DB, migrations []Migration) (*Migrator, error) {
+	sorted := append([]Migration{}, migrations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+
+	for i, m := range sorted {
+		if m.Version == 0 {
+			return nil, fmt.Errorf("migration '%s' has no version", m.Name)
+		}
+		if i > 0 && sorted[i-1].Version == m.Version {
+			return nil, fmt.Errorf("migrations '%s' and '%s' have the same version %d", sorted[i-1].Name, m.Name, m.Version)
+		}
+		if m.Up == nil {
+			return nil, fmt.Errorf("migration %d '%s' has no up step", m.Version, m.Name)
+		}
+	}
+
+	return &Migrator{db: db, migrations: sorted}, nil
+}
+
+func (m *Migrator) LatestVersion() uint {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Create the schema_migrations table if needed. On a DB created before migrations existed, the migrations it already has are detected
+func (m *Migrator) init() error {
+	if m.db.Migrator().HasTable(model.SchemaMigration{}) {
+		return nil
+	}
+
+	return m.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Migrator().CreateTable(model.SchemaMigration{}); err != nil {
+			return fmt.Errorf("failed creating schema_migrations table: %w", err)
+		}
+
+		now := time.Now().UTC()
+		for _, migration := range m.migrations {
+			if migration.Detect == nil || !migration.Detect(tx) {
+				// Next ones are applied normally
+				break
+			}
+			record := model.SchemaMigration{Version: migration.Version, Name: migration.Name, AppliedAt: now, Detected: true}
+			if err := tx.Create(&record).Error; err != nil {
+				return fmt.Errorf("failed recording detected migration %d: %w", migration.Version, err)
+			}
+		}
+		return nil
+	})
+}
+
+func (m *Migrator) applied() (map[uint]model.SchemaMigration, error) {
+	var records []model.SchemaMigration
+	if err := m.db.Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed getting applied migrations: %w", err)
+	}
+	res := make(map[uint]model.SchemaMigration)
+	for _, r := range records {
+		res[r.Version] = r
+	}
+	return res, nil
+}
+
+// All migrations, by increasing version
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+	if err := m.init(); err != nil {
+		return nil, err
+	}
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	var res []MigrationStatus
+	for _, migration := range m.migrations {
+		record, ok := applied[migration.Version]
+		delete(applied, migration.Version)
+		res = append(res, MigrationStatus{
+			Version:   migration.Version,
+			Name:      migration.Name,
+			Applied:   ok,
+			AppliedOn: record.AppliedAt,
+			Detected:  record.Detected,
+		})
+	}
+	for _, record := range applied {
+		res = append(res, MigrationStatus{
+			Version:   record.Version,
+			Name:      record.Name,
+			Applied:   true,
+			AppliedOn: record.AppliedAt,
+			Detected:  record.Detected,
+			Unknown:   true,
+		})
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Version < res[j].Version })
+
+	return res, nil
+}
+
+// Apply the pending migrations up to the target version (included), a zero target applies them all.
+//
+// Return the applied ones
+func (m *Migrator) Up(target uint) ([]Migration, error) {
+	if err := m.init(); err != nil {
+		return nil, err
+	}
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	var res []Migration
+	for _, migration := range m.migrations {
+		if target > 0 && migration.Version > target {
+			break
+		}
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+
+		err := m.db.Transaction(func(tx *gorm.DB) error {
+			if err := migration.Up(tx); err != nil {
+				return err
+			}
+			return tx.Create(&model.SchemaMigration{Version: migration.Version, Name: migration.Name, AppliedAt: time.Now().UTC()}).Error
+		})
+		if err != nil {
+			return res, fmt.Errorf("failed applying migration %d '%s': %w", migration.Version, migration.Name, err)
+		}
+		res = append(res, migration)
+	}
+
+	return res, nil
+}
+
+var ErrIrreversibleMigration = errors.New("migration can't be reverted")
+
+// Revert the applied migrations above the target version, latest first.
+//
+// Return the reverted ones
+func (m *Migrator) Down(target uint) ([]Migration, error) {
+	if err := m.init(); err != nil {
+		return nil, err
+	}
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	var res []Migration
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		migration := m.migrations[i]
+		if migration.Version <= target {
+			break
+		}
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+		if migration.Down == nil {
+			return res, fmt.Errorf("failed reverting migration %d '%s': %w", migration.Version, migration.Name, ErrIrreversibleMigration)
+		}
+
+		err := m.db.Transaction(func(tx *gorm.DB) error {
+			if err := migration.Down(tx); err != nil {
+				return err
+			}
+			return tx.Delete(&model.SchemaMigration{Version: migration.Version}).Error
+		})
+		if err != nil {
+			return res, fmt.Errorf("failed reverting migration %d '%s': %w", migration.Version, migration.Name, err)
+		}
+		res = append(res, migration)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,156 @@
+package db_test
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+
+	"github.com/erwanlbp/trading-bot/pkg/db"
+	"github.com/erwanlbp/trading-bot/pkg/model"
+)
+
+type fruit struct {
+	ID   uint
+	Name string
+}
+
+var testMigrations = []db.Migration{
+	{
+		Version: 1,
+		Name:    "create fruits",
+		Up:      func(tx *gorm.DB) error { return tx.Migrator().CreateTable(fruit{}) },
+		Down:    func(tx *gorm.DB) error { return tx.Migrator().DropTable(fruit{}) },
+		Detect:  func(tx *gorm.DB) bool { return tx.Migrator().HasTable(fruit{}) },
+	},
+	{
+		Version: 2,
+		Name:    "add apple",
+		Up:      func(tx *gorm.DB) error { return tx.Create(&fruit{Name: "apple"}).Error },
+		Down:    func(tx *gorm.DB) error { return tx.Where("name = ?", "apple").Delete(&fruit{}).Error },
+	},
+}
+
+func openDB(t *testing.T) *gorm.DB {
+	database, err := gorm.Open(sqlite.Open("file:"+filepath.Join(t.TempDir(), "test.db")), &gorm.Config{})
+	require.NoError(t, err)
+	return database
+}
+
+func countFruits(t *testing.T, database *gorm.DB) int64 {
+	var count int64
+	require.NoError(t, database.Model(&fruit{}).Count(&count).Error)
+	return count
+}
+
+func TestMigrator(t *testing.T) {
+	t.Parallel()
+
+	database := openDB(t)
+	migrator, err := db.NewMigrator(database, testMigrations)
+	require.NoError(t, err)
+
+	applied, err := migrator.Up(1)
+	require.NoError(t, err)
+	assert.Len(t, applied, 1)
+
+	applied, err = migrator.Up(0)
+	require.NoError(t, err)
+	assert.Len(t, applied, 1)
+	assert.Equal(t, int64(1), countFruits(t, database))
+
+	status, err := migrator.Status()
+	require.NoError(t, err)
+	require.Len(t, status, 2)
+	assert.True(t, status[0].Applied && status[1].Applied)
+
+	reverted, err := migrator.Down(1)
+	require.NoError(t, err)
+	assert.Len(t, reverted, 1)
+	assert.Equal(t, int64(0), countFruits(t, database))
+
+	reverted, err = migrator.Down(0)
+	require.NoError(t, err)
+	assert.Len(t, reverted, 1)
+	assert.False(t, database.Migrator().HasTable(fruit{}))
+}
+
+func TestMigratorDetectsExistingDB(t *testing.T) {
+	t.Parallel()
+
+	database := openDB(t)
+	// Created before migrations existed
+	require.NoError(t, database.Migrator().CreateTable(fruit{}))
+
+	migrator, err := db.NewMigrator(database, testMigrations)
+	require.NoError(t, err)
+
+	applied, err := migrator.Up(0)
+	require.NoError(t, err)
+	require.Len(t, applied, 1)
+	assert.Equal(t, uint(2), applied[0].Version)
+
+	status, err := migrator.Status()
+	require.NoError(t, err)
+	assert.True(t, status[0].Detected)
+	assert.False(t, status[1].Detected)
+}
+
+func TestMigratorRollsBackFailedMigration(t *testing.T) {
+	t.Parallel()
+
+	database := openDB(t)
+	failing := append(append([]db.Migration{}, testMigrations...), db.Migration{
+		Version: 3,
+		Name:    "add pear then fail",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Create(&fruit{Name: "pear"}).Error; err != nil {
+				return err
+			}
+			return errors.New("boom")
+		},
+	})
+	migrator, err := db.NewMigrator(database, failing)
+	require.NoError(t, err)
+
+	applied, err := migrator.Up(0)
+	assert.Error(t, err)
+	assert.Len(t, applied, 2)
+	assert.Equal(t, int64(1), countFruits(t, database))
+
+	status, err := migrator.Status()
+	require.NoError(t, err)
+	assert.False(t, status[2].Applied)
+}
+
+func TestMigrationsMatchModels(t *testing.T) {
+	t.Parallel()
+
+	database := openDB(t)
+	migrator, err := db.NewMigrator(database, db.Migrations)
+	require.NoError(t, err)
+	_, err = migrator.Up(0)
+	require.NoError(t, err)
+
+	for _, m := range []schema.Tabler{
+		model.Coin{}, model.CoinPrice{}, model.CurrentCoin{}, model.Pair{}, model.PairHistory{}, model.Jump{}, model.ManualTrade{},
+		model.Diff{}, model.Chart{}, model.BlacklistedSymbol{}, model.BalanceHistory{},
+		model.VirtualPortfolio{}, model.VirtualJump{}, model.VirtualPair{}, model.VirtualDiff{},
+		model.TakeProfit{}, model.ReserveRelease{}, model.PositionReference{}, model.CircuitBreaker{}, model.JumpProposal{},
+		model.Pause{}, model.Blackout{}, model.UniverseChange{}, model.CoinSuspension{},
+	} {
+		require.True(t, database.Migrator().HasTable(m), m.TableName())
+		stmt := &gorm.Statement{DB: database}
+		require.NoError(t, stmt.Parse(m))
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName != "" {
+				assert.True(t, database.Migrator().HasColumn(m, field.DBName), "%s.%s", m.TableName(), field.DBName)
+			}
+		}
+	}
+}
@@ -0,0 +1,254 @@
+package db
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// Schema changes, append new ones with the next version. Never edit an existing one, it won't run again on DBs that already applied it.
+//
+// Migrations must not rely on the models, they change over time: declare the structs they need, as they are at that version
+var Migrations = []Migration{
+	{
+		Version: 1,
+		Name:    "baseline",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(baselineModels...)
+		},
+		// An older DB missing some tables or columns is not detected, AutoMigrate completes it
+		Detect: func(tx *gorm.DB) bool {
+			for _, m := range baselineModels {
+				if !hasModel(tx, m) {
+					return false
+				}
+			}
+			return true
+		},
+	},
+	{
+		Version: 2,
+		Name:    "history buckets",
+		Up:      addHistoryBuckets,
+		Down: func(tx *gorm.DB) error {
+			if err := dropModelColumns(tx, coinPriceBucketV2{}); err != nil {
+				return err
+			}
+			return dropModelColumns(tx, pairHistoryBucketV2{})
+		},
+	},
+	{
+		Version: 3,
+		Name:    "backfilled history",
+		Up: func(tx *gorm.DB) error {
+			if err := addMissingColumns(tx, coinPriceBackfillV3{}); err != nil {
+				return err
+			}
+			return addMissingColumns(tx, pairHistoryBackfillV3{})
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := dropModelColumns(tx, coinPriceBackfillV3{}); err != nil {
+				return err
+			}
+			return dropModelColumns(tx, pairHistoryBackfillV3{})
+		},
+	},
+	{
+		Version: 4,
+		Name:    "balance in bridge",
+		Up: func(tx *gorm.DB) error {
+			return addMissingColumns(tx, balanceHistoryBridgeV4{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return dropModelColumns(tx, balanceHistoryBridgeV4{})
+		},
+	},
+	{
+		Version: 5,
+		Name:    "flagged pairs history",
+		Up: func(tx *gorm.DB) error {
+			return addMissingColumns(tx, pairHistoryAnomalyV5{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return dropModelColumns(tx, pairHistoryAnomalyV5{})
+		},
+	},
+	{
+		Version: 6,
+		Name:    "manual trades",
+		Up:      addManualTrades,
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(manualTradeV6{})
+		},
+	},
+	{
+		Version: 7,
+		Name:    "reserve releases",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(reserveReleaseV7{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(reserveReleaseV7{})
+		},
+	},
+}
+
+// The table of the model exists, with all its columns
+func hasModel(tx *gorm.DB, m interface{}) bool {
+	if !tx.Migrator().HasTable(m) {
+		return false
+	}
+	stmt := &gorm.Statement{DB: tx}
+	if err := stmt.Parse(m); err != nil {
+		return false
+	}
+	for _, field := range stmt.Schema.Fields {
+		if field.DBName != "" && !tx.Migrator().HasColumn(m, field.DBName) {
+			return false
+		}
+	}
+	return true
+}
+
+type coinPriceBucketV2 struct {
+	Resolution time.Duration   `gorm:"default:0"`
+	Samples    int64           `gorm:"default:0"`
+	MinPrice   decimal.Decimal `gorm:"default:0"`
+	MaxPrice   decimal.Decimal `gorm:"default:0"`
+	LastPrice  decimal.Decimal `gorm:"default:0"`
+}
+
+func (coinPriceBucketV2) TableName() string { return "coin_price_history" }
+
+type pairHistoryBucketV2 struct {
+	Resolution time.Duration   `gorm:"default:0"`
+	Samples    int64           `gorm:"default:0"`
+	MinRatio   decimal.Decimal `gorm:"default:0"`
+	MaxRatio   decimal.Decimal `gorm:"default:0"`
+	LastRatio  decimal.Decimal `gorm:"default:0"`
+}
+
+func (pairHistoryBucketV2) TableName() string { return "pairs_history" }
+
+// Min/max/last columns of the downsampled history. The hourly rows of the old cleaner are marked as 1h buckets
+func addHistoryBuckets(tx *gorm.DB) error {
+	for _, m := range []interface{}{coinPriceBucketV2{}, pairHistoryBucketV2{}} {
+		if err := addMissingColumns(tx, m); err != nil {
+			return err
+		}
+	}
+
+	if tx.Dialector.Name() == DialectSQLite {
+		// The old cleaner wrote hourly timestamps without timezone, unlike the driver
+		for _, table := range []string{"coin_price_history", "pairs_history"} {
+			if err := tx.Exec("UPDATE "+table+" SET timestamp = timestamp || '+00:00' WHERE averaged = ? AND length(timestamp) = 19", true).Error; err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := tx.Exec("UPDATE coin_price_history SET resolution = ?, min_price = price, max_price = price, last_price = price WHERE averaged = ?", time.Hour, true).Error; err != nil {
+		return err
+	}
+	return tx.Exec("UPDATE pairs_history SET resolution = ?, min_ratio = ratio, max_ratio = ratio, last_ratio = ratio WHERE averaged = ?", time.Hour, true).Error
+}
+
+type coinPriceBackfillV3 struct {
+	Backfilled bool `gorm:"default:false"`
+}
+
+func (coinPriceBackfillV3) TableName() string { return "coin_price_history" }
+
+type pairHistoryBackfillV3 struct {
+	Backfilled bool `gorm:"default:false"`
+}
+
+func (pairHistoryBackfillV3) TableName() string { return "pairs_history" }
+
+type balanceHistoryBridgeV4 struct {
+	Bridge        string
+	BridgeBalance decimal.Decimal `gorm:"default:0"`
+}
+
+func (balanceHistoryBridgeV4) TableName() string { return "balance_history" }
+
+type pairHistoryAnomalyV5 struct {
+	Anomaly bool `gorm:"default:false"`
+}
+
+func (pairHistoryAnomalyV5) TableName() string { return "pairs_history" }
+
+type manualTradeV6 struct {
+	ID             uint `gorm:"primaryKey"`
+	Timestamp      time.Time
+	Side           string
+	Coin           string
+	Bridge         string
+	Price          decimal.Decimal
+	Quantity       decimal.Decimal
+	BridgeQuantity decimal.Decimal
+}
+
+func (manualTradeV6) TableName() string { return "manual_trades" }
+
+// Manual exits and entries were saved as jumps from/to the bridge, which isn't in the coins table
+func addManualTrades(tx *gorm.DB) error {
+	if err := tx.Migrator().CreateTable(manualTradeV6{}); err != nil {
+		return err
+	}
+
+	const notACoin = "NOT IN (SELECT coin FROM coins)"
+	if err := tx.Exec("INSERT INTO manual_trades (timestamp, side, coin, bridge, price, quantity, bridge_quantity) "+
+		"SELECT timestamp, 'exit', from_coin, to_coin, from_price, from_quantity, to_quantity FROM jumps WHERE manual = ? AND to_coin "+notACoin, true).Error; err != nil {
+		return err
+	}
+	if err := tx.Exec("INSERT INTO manual_trades (timestamp, side, coin, bridge, price, quantity, bridge_quantity) "+
+		"SELECT timestamp, 'entry', to_coin, from_coin, to_price, to_quantity, from_quantity FROM jumps WHERE manual = ? AND from_coin "+notACoin, true).Error; err != nil {
+		return err
+	}
+	return tx.Exec("DELETE FROM jumps WHERE manual = ? AND (from_coin "+notACoin+" OR to_coin "+notACoin+")", true).Error
+}
+
+type reserveReleaseV7 struct {
+	ID        uint `gorm:"primaryKey"`
+	Timestamp time.Time
+	Amount    decimal.Decimal
+	Reason    string
+}
+
+func (reserveReleaseV7) TableName() string { return "reserve_releases" }
+
+// DBs created by AutoMigrate before versioned migrations may already have them
+func addMissingColumns(tx *gorm.DB, m interface{}) error {
+	stmt := &gorm.Statement{DB: tx}
+	if err := stmt.Parse(m); err != nil {
+		return err
+	}
+	for _, field := range stmt.Schema.Fields {
+		if tx.Migrator().HasColumn(m, field.DBName) {
+			continue
+		}
+		if err := tx.Migrator().AddColumn(m, field.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Drop the columns declared by the migration struct
+func dropModelColumns(tx *gorm.DB, m interface{}) error {
+	stmt := &gorm.Statement{DB: tx}
+	if err := stmt.Parse(m); err != nil {
+		return err
+	}
+	for _, field := range stmt.Schema.Fields {
+		if !tx.Migrator().HasColumn(m, field.DBName) {
+			continue
+		}
+		if err := tx.Migrator().DropColumn(m, field.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,286 @@
+package db
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Schema of the baseline, as the models were when migrations were introduced. Later changes are migrations
+var baselineModels = []interface{}{
+	coinV1{},
+	coinPriceV1{},
+	currentCoinV1{},
+	pairV1{},
+	pairHistoryV1{},
+	jumpV1{},
+	diffV1{},
+	chartV1{},
+	blacklistedSymbolV1{},
+	balanceHistoryV1{},
+	virtualPortfolioV1{},
+	virtualJumpV1{},
+	virtualPairV1{},
+	virtualDiffV1{},
+	takeProfitV1{},
+	positionReferenceV1{},
+	circuitBreakerV1{},
+	jumpProposalV1{},
+	pauseV1{},
+	blackoutV1{},
+	universeChangeV1{},
+	coinSuspensionV1{},
+}
+
+type coinV1 struct {
+	Coin      string `gorm:"primaryKey"`
+	Enabled   bool
+	EnabledOn time.Time
+}
+
+func (coinV1) TableName() string { return "coins" }
+
+type coinPriceV1 struct {
+	Coin      string    `gorm:"primaryKey"`
+	AltCoin   string    `gorm:"primaryKey"`
+	Timestamp time.Time `gorm:"primaryKey"`
+	Price     decimal.Decimal
+	BidPrice  decimal.Decimal `gorm:"default:0"`
+	AskPrice  decimal.Decimal `gorm:"default:0"`
+	Averaged  bool
+
+	CoinRef coinV1 `gorm:"foreignKey:Coin;references:Coin"`
+}
+
+func (coinPriceV1) TableName() string { return "coin_price_history" }
+
+type currentCoinV1 struct {
+	Coin      string    `gorm:"primaryKey"`
+	Timestamp time.Time `gorm:"primaryKey"`
+}
+
+func (currentCoinV1) TableName() string { return "current_coin_history" }
+
+type pairV1 struct {
+	ID       uint `gorm:"primaryKey;autoIncrement"`
+	FromCoin string
+	ToCoin   string
+	Exists   bool
+
+	LastJump             time.Time
+	LastJumpRatio        decimal.Decimal
+	LastJumpRatioBasedOn time.Time
+
+	FromCoinDetail coinV1 `gorm:"foreignKey:FromCoin"`
+	ToCoinDetail   coinV1 `gorm:"foreignKey:ToCoin"`
+}
+
+func (pairV1) TableName() string { return "pairs" }
+
+type pairHistoryV1 struct {
+	PairID    uint      `gorm:"primaryKey"`
+	Timestamp time.Time `gorm:"primaryKey"`
+	Ratio     decimal.Decimal
+	Averaged  bool
+
+	Pair pairV1 `gorm:"foreignKey:PairID;references:ID"`
+}
+
+func (pairHistoryV1) TableName() string { return "pairs_history" }
+
+type jumpV1 struct {
+	FromCoin  string    `gorm:"primaryKey"`
+	ToCoin    string    `gorm:"primaryKey"`
+	Timestamp time.Time `gorm:"primaryKey"`
+
+	FromQuantity decimal.Decimal
+	FromPrice    decimal.Decimal
+	ToQuantity   decimal.Decimal
+	ToPrice      decimal.Decimal
+
+	ExpectedSlippage decimal.Decimal `gorm:"default:0"`
+	RealizedSlippage decimal.Decimal `gorm:"default:0"`
+
+	Manual bool `gorm:"default:false"`
+
+	FromCoinRef coinV1 `gorm:"foreignKey:FromCoin;references:Coin"`
+	ToCoinRef   coinV1 `gorm:"foreignKey:ToCoin;references:Coin"`
+}
+
+func (jumpV1) TableName() string { return "jumps" }
+
+type diffV1 struct {
+	FromCoin   string    `gorm:"primaryKey"`
+	ToCoin     string    `gorm:"primaryKey"`
+	Timestamp  time.Time `gorm:"primaryKey"`
+	Diff       decimal.Decimal
+	NeededDiff decimal.Decimal
+}
+
+func (diffV1) TableName() string { return "diff" }
+
+type chartV1 struct {
+	ID     uint `gorm:"primaryKey;autoIncrement"`
+	Type   string
+	Config string
+}
+
+func (chartV1) TableName() string { return "chart" }
+
+type blacklistedSymbolV1 struct {
+	Symbol string `gorm:"primaryKey"`
+}
+
+func (blacklistedSymbolV1) TableName() string { return "blacklisted_symbol" }
+
+type balanceHistoryV1 struct {
+	Timestamp   time.Time `gorm:"primaryKey"`
+	BtcBalance  decimal.Decimal
+	UsdtBalance decimal.Decimal
+}
+
+func (balanceHistoryV1) TableName() string { return "balance_history" }
+
+type virtualPortfolioV1 struct {
+	Strategy string `gorm:"primaryKey"`
+	Coin     string
+	Quantity decimal.Decimal
+	LastJump time.Time
+
+	StartBalance decimal.Decimal
+	StartedOn    time.Time
+}
+
+func (virtualPortfolioV1) TableName() string { return "virtual_portfolio" }
+
+type virtualJumpV1 struct {
+	Strategy  string    `gorm:"primaryKey"`
+	FromCoin  string    `gorm:"primaryKey"`
+	ToCoin    string    `gorm:"primaryKey"`
+	Timestamp time.Time `gorm:"primaryKey"`
+
+	FromQuantity decimal.Decimal
+	FromPrice    decimal.Decimal
+	ToQuantity   decimal.Decimal
+	ToPrice      decimal.Decimal
+}
+
+func (virtualJumpV1) TableName() string { return "virtual_jumps" }
+
+type virtualPairV1 struct {
+	Strategy string `gorm:"primaryKey"`
+	FromCoin string `gorm:"primaryKey"`
+	ToCoin   string `gorm:"primaryKey"`
+
+	LastJumpRatio        decimal.Decimal
+	LastJumpRatioBasedOn time.Time
+}
+
+func (virtualPairV1) TableName() string { return "virtual_pairs" }
+
+type virtualDiffV1 struct {
+	Strategy   string    `gorm:"primaryKey"`
+	FromCoin   string    `gorm:"primaryKey"`
+	ToCoin     string    `gorm:"primaryKey"`
+	Timestamp  time.Time `gorm:"primaryKey"`
+	Diff       decimal.Decimal
+	NeededDiff decimal.Decimal
+}
+
+func (virtualDiffV1) TableName() string { return "virtual_diff" }
+
+type takeProfitV1 struct {
+	Timestamp time.Time `gorm:"primaryKey"`
+	Coin      string
+
+	ReferenceValue decimal.Decimal
+	Value          decimal.Decimal
+
+	SoldQuantity   decimal.Decimal
+	SoldPrice      decimal.Decimal
+	Proceeds       decimal.Decimal
+	RealizedProfit decimal.Decimal
+
+	Banked bool
+}
+
+func (takeProfitV1) TableName() string { return "take_profits" }
+
+type positionReferenceV1 struct {
+	ID        uint `gorm:"primaryKey"`
+	Timestamp time.Time
+	Value     decimal.Decimal
+}
+
+func (positionReferenceV1) TableName() string { return "position_reference" }
+
+type circuitBreakerV1 struct {
+	ID uint `gorm:"primaryKey"`
+
+	Tripped   bool
+	Reason    string
+	TrippedOn time.Time
+	ResumedOn time.Time
+
+	ConsecutiveFailedJumps int
+}
+
+func (circuitBreakerV1) TableName() string { return "circuit_breaker" }
+
+type jumpProposalV1 struct {
+	ID        uint `gorm:"primaryKey"`
+	CreatedOn time.Time
+	Path      string
+
+	Diff       decimal.Decimal
+	NeededDiff decimal.Decimal
+	Fee        decimal.Decimal
+
+	FromQuantity       decimal.Decimal
+	ExpectedToQuantity decimal.Decimal
+
+	Status      string
+	DecidedOn   time.Time
+	RecheckDiff decimal.Decimal `gorm:"default:0"`
+}
+
+func (jumpProposalV1) TableName() string { return "jump_proposals" }
+
+type pauseV1 struct {
+	ID           uint `gorm:"primaryKey"`
+	Paused       bool
+	Reason       string
+	PausedOn     time.Time
+	Until        time.Time
+	LastReminder time.Time
+}
+
+func (pauseV1) TableName() string { return "pause" }
+
+type blackoutV1 struct {
+	ID       uint `gorm:"primaryKey"`
+	StartsAt time.Time
+	EndsAt   time.Time
+	Reason   string
+}
+
+func (blackoutV1) TableName() string { return "blackouts" }
+
+type universeChangeV1 struct {
+	ID        uint `gorm:"primaryKey"`
+	Timestamp time.Time
+	Coin      string
+	Added     bool
+}
+
+func (universeChangeV1) TableName() string { return "universe_changes" }
+
+type coinSuspensionV1 struct {
+	Coin        string `gorm:"primaryKey"`
+	Active      bool
+	SuspendedOn time.Time
+	Until       time.Time
+	Reason      string
+}
+
+func (coinSuspensionV1) TableName() string { return "coin_suspensions" }
@@ -0,0 +1,105 @@
+package postgres
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"reflect"
+	"time"
+
+	extraClausePlugin "github.com/WinterYukky/gorm-extra-clause-plugin"
+	"github.com/shopspring/decimal"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gorm_logger "gorm.io/gorm/logger"
+	"gorm.io/gorm/migrator"
+	"gorm.io/gorm/schema"
+
+	"github.com/erwanlbp/trading-bot/pkg/log"
+	"github.com/erwanlbp/trading-bot/pkg/log/zapgorm2"
+)
+
+func NewDB(logger *log.Logger, dsn string) (*gorm.DB, error) {
+	theDatabase, err := gorm.Open(dialector{Dialector: postgres.Open(dsn).(*postgres.Dialector)}, &gorm.Config{
+		Logger: zapgorm2.Logger{
+			ZapLogger:                 logger.Logger,
+			LogLevel:                  gorm_logger.Error,
+			SlowThreshold:             1 * time.Second,
+			IgnoreRecordNotFoundError: true,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to init db: %w", err)
+	}
+
+	if err := theDatabase.Use(extraClausePlugin.New()); err != nil {
+		return nil, fmt.Errorf("failed to add extra clause plugin: %w", err)
+	}
+
+	return theDatabase, nil
+}
+
+var decimalType = reflect.TypeOf(decimal.Decimal{})
+
+// Decimals are stored as text by default, which postgres can't sum or compare as numbers
+type dialector struct {
+	*postgres.Dialector
+}
+
+func (d dialector) DataTypeOf(field *schema.Field) string {
+	if field.FieldType == decimalType {
+		return "numeric"
+	}
+	return d.Dialector.DataTypeOf(field)
+}
+
+// Same as the postgres one, but with this dialector to get the decimal type
+func (d dialector) Migrator(db *gorm.DB) gorm.Migrator {
+	return postgres.Migrator{Migrator: migrator.Migrator{Config: migrator.Config{
+		DB:                          db,
+		Dialector:                   d,
+		CreateIndexAfterCreateTable: true,
+	}}}
+}
+
+// Dump the database in the pg_dump custom format, restore it with pg_restore. pg_dump must be installed
+func Dump(ctx context.Context, dsn string) (io.Reader, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "pg_dump", "--format=custom", "--dbname="+dsn)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("pg_dump failed: %w: %s", err, stderr.String())
+	}
+	return &stdout, nil
+}
+
+// Same as Dump, into a file
+func DumpToFile(ctx context.Context, dsn, path string) error {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "pg_dump", "--format=custom", "--dbname="+dsn, "--file="+path)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("pg_dump failed: %w: %s", err, stderr.String())
+	}
+	return nil
+}
+
+// Replace the content of the database by the dump
+func Restore(ctx context.Context, dsn, path string) error {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "pg_restore", "--clean", "--if-exists", "--no-owner", "--dbname="+dsn, path)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("pg_restore failed: %w: %s", err, stderr.String())
+	}
+	return nil
+}
+
+func Size(db *gorm.DB) (int64, error) {
+	var size int64
+	err := db.Raw("SELECT pg_database_size(current_database())").Scan(&size).Error
+	return size, err
+}
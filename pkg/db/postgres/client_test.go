@@ -0,0 +1,162 @@
+package postgres_test
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/erwanlbp/trading-bot/pkg/config/configfile"
+	"github.com/erwanlbp/trading-bot/pkg/db"
+	"github.com/erwanlbp/trading-bot/pkg/db/postgres"
+	"github.com/erwanlbp/trading-bot/pkg/log"
+	"github.com/erwanlbp/trading-bot/pkg/model"
+	"github.com/erwanlbp/trading-bot/pkg/repository"
+)
+
+// Database of the DSN is wiped, only use a throwaway one like the docker-compose.test.yml one, see `make test-postgres`
+const dsnEnv = "TEST_POSTGRES_DSN"
+
+func TestPostgres(t *testing.T) {
+	dsn := os.Getenv(dsnEnv)
+	if dsn == "" {
+		t.Skip(dsnEnv + " is not set")
+	}
+
+	logger := log.NewSimpleZapLogger()
+	gormDB, err := postgres.NewDB(logger, dsn)
+	require.NoError(t, err)
+	require.NoError(t, gormDB.Exec("DROP SCHEMA public CASCADE").Error)
+	require.NoError(t, gormDB.Exec("CREATE SCHEMA public").Error)
+
+	database := db.NewDB(gormDB)
+	require.NoError(t, database.MigrateSchema())
+	assert.Equal(t, db.DialectPostgres, database.Dialect())
+
+	// Decimals must be numbers to be summed and compared
+	var dataType string
+	require.NoError(t, gormDB.Raw("SELECT data_type FROM information_schema.columns WHERE table_name = ? AND column_name = ?", model.JumpTableName, "from_price").Scan(&dataType).Error)
+	assert.Equal(t, "numeric", dataType)
+
+	repo := repository.NewRepository(database, &configfile.ConfigFile{Bridge: "USDT"}, logger)
+
+	require.NoError(t, repository.SimpleUpsert(gormDB, model.Coin{Coin: "BTC", Enabled: true}, model.Coin{Coin: "ETH", Enabled: true}, model.Coin{Coin: "SOL", Enabled: true}))
+
+	start := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	jump := func(from, to string, hours int, fromPrice, toPrice string) model.Jump {
+		return model.Jump{
+			FromCoin:     from,
+			ToCoin:       to,
+			Timestamp:    start.Add(time.Duration(hours) * time.Hour),
+			FromQuantity: decimal.NewFromInt(1),
+			FromPrice:    decimal.RequireFromString(fromPrice),
+			ToQuantity:   decimal.NewFromInt(1),
+			ToPrice:      decimal.RequireFromString(toPrice),
+		}
+	}
+	jumps := []model.Jump{
+		jump("BTC", "ETH", 1, "60000", "3000"),
+		jump("ETH", "SOL", 2, "3300", "150"),
+		jump("SOL", "BTC", 3, "140", "61000"),
+	}
+	require.NoError(t, repository.SimpleUpsert(gormDB, jumps...))
+
+	t.Run("upsert", func(t *testing.T) {
+		updated := jumps[0]
+		updated.ToPrice = decimal.RequireFromString("3010")
+		require.NoError(t, repository.SimpleUpsert(gormDB, updated))
+
+		saved, err := repo.GetJumps(repository.OrderBy("timestamp"))
+		require.NoError(t, err)
+		require.Len(t, saved, 3)
+		assert.Equal(t, "3010", saved[0].ToPrice.String())
+	})
+
+	t.Run("last holdings", func(t *testing.T) {
+		holdings, err := repo.GetLastHoldings(5)
+		require.NoError(t, err)
+
+		require.Len(t, holdings["ETH"], 1)
+		eth := holdings["ETH"][0]
+		assert.True(t, eth.EnteredOn.Equal(start.Add(time.Hour)))
+		assert.True(t, eth.ExitedOn.Equal(start.Add(2*time.Hour)))
+		assert.Equal(t, "3010", eth.EntryPrice.String())
+		assert.Equal(t, "3300", eth.ExitPrice.String())
+
+		assert.Len(t, holdings["SOL"], 1)
+		assert.Empty(t, holdings["BTC"], "still held")
+	})
+
+	t.Run("bridge reserve", func(t *testing.T) {
+		require.NoError(t, repository.SimpleUpsert(gormDB,
+			model.TakeProfit{Timestamp: start, Coin: "ETH", Proceeds: decimal.NewFromInt(100), Banked: true},
+			model.TakeProfit{Timestamp: start.Add(time.Hour), Coin: "SOL", Proceeds: decimal.NewFromInt(50), Banked: true},
+			model.TakeProfit{Timestamp: start.Add(2 * time.Hour), Coin: "BTC", Proceeds: decimal.NewFromInt(10)},
+		))
+		require.NoError(t, repo.ReleaseBridgeReserve(decimal.NewFromInt(30), model.ReserveReleasedManually, start))
+
+		reserve, err := repo.GetBridgeReserve()
+		require.NoError(t, err)
+		assert.Equal(t, "120", reserve.String())
+
+		reserve, err = repo.CapBridgeReserve(decimal.NewFromInt(80))
+		require.NoError(t, err)
+		assert.Equal(t, "80", reserve.String())
+		reserve, err = repo.GetBridgeReserve()
+		require.NoError(t, err)
+		assert.Equal(t, "80", reserve.String())
+	})
+
+	t.Run("backfilled prices", func(t *testing.T) {
+		prices := []model.CoinPrice{
+			{Coin: "BTC", AltCoin: "USDT", Timestamp: start, Price: decimal.NewFromInt(60000), Backfilled: true},
+			{Coin: "ETH", AltCoin: "USDT", Timestamp: start, Price: decimal.NewFromInt(3000), Backfilled: true},
+		}
+		inserted, err := repo.SaveBackfilledPrices(prices)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), inserted)
+
+		// Already there, nothing is inserted
+		inserted, err = repo.SaveBackfilledPrices(prices)
+		require.NoError(t, err)
+		assert.Zero(t, inserted)
+
+		last, err := repo.GetCoinsLastPrice("USDT")
+		require.NoError(t, err)
+		assert.Len(t, last, 2)
+	})
+
+	t.Run("vacuum and size", func(t *testing.T) {
+		require.NoError(t, repo.Vacuum())
+
+		size, err := postgres.Size(gormDB)
+		require.NoError(t, err)
+		assert.Positive(t, size)
+	})
+
+	t.Run("dump and restore", func(t *testing.T) {
+		for _, tool := range []string{"pg_dump", "pg_restore"} {
+			if _, err := exec.LookPath(tool); err != nil {
+				t.Skip(tool + " is not installed")
+			}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		path := filepath.Join(t.TempDir(), "test.dump")
+		require.NoError(t, postgres.DumpToFile(ctx, dsn, path))
+
+		require.NoError(t, gormDB.Exec("DELETE FROM "+model.JumpTableName).Error)
+		require.NoError(t, postgres.Restore(ctx, dsn, path))
+
+		saved, err := repo.GetJumps()
+		require.NoError(t, err)
+		assert.Len(t, saved, 3)
+	})
+}
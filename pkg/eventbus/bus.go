@@ -5,35 +5,91 @@ import "sync"
 type Bus struct {
 	mtx           sync.RWMutex
 	subscriptions []*Subscription
+	closed        bool
 }
 
 func NewEventBus() *Bus {
 	return &Bus{}
 }
 
+// Queue the event in the subscriptions listening to it, only subscriptions with the Block policy can make it wait
 func (b *Bus) Notify(event Event) {
-	go func() {
-		b.mtx.RLock()
-		defer b.mtx.RUnlock()
-
-		for i := 0; i < len(b.subscriptions); i += 1 {
-			sub := b.subscriptions[i]
-
-			if _, ok := sub.EventsSubscribed[event.Name]; ok {
-				// TODO What if this sub is not listening right now, that'll block this func
-				sub.EventsCh <- event
-			}
+	b.mtx.RLock()
+	var subs []*Subscription
+	for _, sub := range b.subscriptions {
+		if sub.IsSubscribed(event.Name) {
+			subs = append(subs, sub)
 		}
-	}()
+	}
+	b.mtx.RUnlock()
+
+	// Outside of the lock, a blocked subscription must not prevent others to unsubscribe
+	for _, sub := range subs {
+		sub.push(event)
+	}
 }
 
 func (b *Bus) Subscribe(events ...string) *Subscription {
-	sub := newSubscription(events)
+	return b.SubscribeWith(DefaultSubscriptionOptions, events...)
+}
+
+// Subscribe to every event going through the bus
+func (b *Bus) SubscribeAll(options SubscriptionOptions) *Subscription {
+	sub := newSubscription(b, nil, options)
+	sub.allEvents = true
+	return b.add(sub)
+}
 
+func (b *Bus) SubscribeWith(options SubscriptionOptions, events ...string) *Subscription {
+	return b.add(newSubscription(b, events, options))
+}
+
+func (b *Bus) add(sub *Subscription) *Subscription {
 	b.mtx.Lock()
 	defer b.mtx.Unlock()
 
+	if b.closed {
+		sub.stop()
+		return sub
+	}
+
 	b.subscriptions = append(b.subscriptions, sub)
 
 	return sub
 }
+
+func (b *Bus) unsubscribe(sub *Subscription) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	for i, s := range b.subscriptions {
+		if s == sub {
+			b.subscriptions = append(b.subscriptions[:i], b.subscriptions[i+1:]...)
+			return
+		}
+	}
+}
+
+// Close all subscriptions, next ones are closed right away
+func (b *Bus) Close() {
+	b.mtx.Lock()
+	b.closed = true
+	subs := b.subscriptions
+	b.subscriptions = nil
+	b.mtx.Unlock()
+
+	for _, sub := range subs {
+		sub.Close()
+	}
+}
+
+func (b *Bus) Stats() []SubscriptionStats {
+	b.mtx.RLock()
+	defer b.mtx.RUnlock()
+
+	var res []SubscriptionStats
+	for _, sub := range b.subscriptions {
+		res = append(res, sub.Stats())
+	}
+	return res
+}
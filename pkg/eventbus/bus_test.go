@@ -0,0 +1,115 @@
+package eventbus_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/erwanlbp/trading-bot/pkg/eventbus"
+)
+
+// Handle the pending events then stop
+func drain(sub *eventbus.Subscription) []interface{} {
+	var res []interface{}
+	ctx, cancel := context.WithCancel(context.Background())
+	sub.Handler(ctx, func(_ context.Context, e eventbus.Event) {
+		res = append(res, e.Payload)
+		if sub.Stats().Pending == 0 {
+			cancel()
+		}
+	})
+	return res
+}
+
+func TestOverflowPolicies(t *testing.T) {
+	t.Parallel()
+
+	for _, c := range []struct {
+		policy   eventbus.OverflowPolicy
+		expected []interface{}
+		dropped  uint64
+	}{
+		{policy: eventbus.DropNewest, expected: []interface{}{1, 2}, dropped: 2},
+		{policy: eventbus.DropOldest, expected: []interface{}{3, 4}, dropped: 2},
+		{policy: eventbus.Coalesce, expected: []interface{}{4, "other"}, dropped: 3},
+	} {
+		c := c
+		t.Run(c.policy.String(), func(t *testing.T) {
+			t.Parallel()
+
+			bus := eventbus.NewEventBus()
+			sub := bus.SubscribeWith(eventbus.SubscriptionOptions{BufferSize: 2, Policy: c.policy}, "tick", "other")
+
+			for i := 1; i <= 4; i++ {
+				bus.Notify(eventbus.Event{Name: "tick", Payload: i})
+				if i == 1 && c.policy == eventbus.Coalesce {
+					bus.Notify(eventbus.Event{Name: "other", Payload: "other"})
+				}
+			}
+			bus.Notify(eventbus.Event{Name: "not_subscribed", Payload: 0})
+
+			assert.Equal(t, c.expected, drain(sub))
+			stats := sub.Stats()
+			assert.Equal(t, c.dropped, stats.Dropped)
+			assert.Equal(t, uint64(len(c.expected)), stats.Delivered)
+		})
+	}
+}
+
+func TestBlockPolicy(t *testing.T) {
+	t.Parallel()
+
+	bus := eventbus.NewEventBus()
+	sub := bus.SubscribeWith(eventbus.SubscriptionOptions{BufferSize: 1, Policy: eventbus.Block}, "tick")
+
+	published := make(chan struct{})
+	go func() {
+		bus.Notify(eventbus.Event{Name: "tick", Payload: 1})
+		bus.Notify(eventbus.Event{Name: "tick", Payload: 2})
+		close(published)
+	}()
+
+	select {
+	case <-published:
+		t.Fatal("second event should wait for room in the buffer")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	got := make(chan interface{}, 2)
+	go sub.Handler(context.Background(), func(_ context.Context, e eventbus.Event) { got <- e.Payload })
+
+	<-published
+	assert.Equal(t, 1, <-got)
+	assert.Equal(t, 2, <-got)
+}
+
+func TestCloseAndCancel(t *testing.T) {
+	t.Parallel()
+
+	bus := eventbus.NewEventBus()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sub := bus.Subscribe("tick")
+	stopped := make(chan struct{})
+	go func() {
+		sub.Handler(ctx, func(context.Context, eventbus.Event) {})
+		close(stopped)
+	}()
+	cancel()
+	<-stopped
+	assert.Empty(t, bus.Stats(), "handler exit unsubscribes")
+
+	closed := bus.SubscribeWith(eventbus.SubscriptionOptions{BufferSize: 1, Policy: eventbus.Block}, "tick")
+	closed.Close()
+	// Would block forever if the closed subscription was still fed
+	bus.Notify(eventbus.Event{Name: "tick", Payload: 1})
+	bus.Notify(eventbus.Event{Name: "tick", Payload: 2})
+	assert.Empty(t, bus.Stats())
+
+	bus.Close()
+	late := bus.Subscribe("tick")
+	bus.Notify(eventbus.Event{Name: "tick", Payload: 1})
+	assert.Equal(t, 0, late.Stats().Pending)
+}
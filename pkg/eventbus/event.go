@@ -1,24 +1,14 @@
 package eventbus
 
+// Untyped form of the events, as they go through the bus. Use the topics to publish and subscribe
 type Event struct {
 	Name    string
 	Payload interface{}
 }
 
-const (
-	EventCoinsPricesFetched    = "coins_prices_fetched"
-	EventFoundUnexistingSymbol = "found_unexisting_symbol"
-	SendNotification           = "send_notification"
-	SaveBalance                = "save_balance"
+var (
+	CoinsPricesFetched    = NewTopic[NoPayload]("coins_prices_fetched")
+	FoundUnexistingSymbol = NewTopic[string]("found_unexisting_symbol")
+	SendNotification      = NewTopic[string]("send_notification")
+	SaveBalance           = NewTopic[NoPayload]("save_balance")
 )
-
-func GenerateEvent(eventName string, payload interface{}) Event {
-	return Event{
-		Name:    eventName,
-		Payload: payload,
-	}
-}
-
-func FoundUnexistingSymbol(symbol string) Event {
-	return GenerateEvent(EventFoundUnexistingSymbol, symbol)
-}
@@ -0,0 +1,217 @@
+package eventbus
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// One line of a journal file
+type JournalRecord struct {
+	Time    time.Time       `json:"time"`
+	Name    string          `json:"name"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+const (
+	journalPrefix = "events"
+	journalExt    = ".jsonl"
+)
+
+// Append events as JSON lines to <dir>/events.jsonl. When it gets bigger than maxSize, it's renamed with its rotation time and a new one is started.
+//
+// Only the last maxFiles rotated files are kept, a zero maxFiles keeps them all
+type JournalWriter struct {
+	dir      string
+	maxSize  int64
+	maxFiles int
+
+	mtx  sync.Mutex
+	file *os.File
+	size int64
+}
+
+func NewJournalWriter(dir string, maxSize int64, maxFiles int) (*JournalWriter, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed creating journal dir: %w", err)
+	}
+	w := &JournalWriter{dir: dir, maxSize: maxSize, maxFiles: maxFiles}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *JournalWriter) currentPath() string {
+	return filepath.Join(w.dir, journalPrefix+journalExt)
+}
+
+func (w *JournalWriter) open() error {
+	file, err := os.OpenFile(w.currentPath(), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed opening journal: %w", err)
+	}
+	stat, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed reading journal size: %w", err)
+	}
+	w.file = file
+	w.size = stat.Size()
+	return nil
+}
+
+func (w *JournalWriter) Write(t time.Time, event Event) error {
+	payload, err := json.Marshal(event.Payload)
+	if err != nil {
+		return fmt.Errorf("failed marshaling payload of '%s': %w", event.Name, err)
+	}
+	line, err := json.Marshal(JournalRecord{Time: t.UTC(), Name: event.Name, Payload: payload})
+	if err != nil {
+		return fmt.Errorf("failed marshaling record of '%s': %w", event.Name, err)
+	}
+	line = append(line, '\n')
+
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	if w.file == nil {
+		return fmt.Errorf("journal is closed")
+	}
+
+	if w.maxSize > 0 && w.size > 0 && w.size+int64(len(line)) > w.maxSize {
+		if err := w.rotate(t); err != nil {
+			return err
+		}
+	}
+
+	n, err := w.file.Write(line)
+	w.size += int64(n)
+	return err
+}
+
+func (w *JournalWriter) rotate(t time.Time) error {
+	if err := w.file.Close(); err != nil {
+		return fmt.Errorf("failed closing journal: %w", err)
+	}
+	rotated := filepath.Join(w.dir, fmt.Sprintf("%s-%s%s", journalPrefix, t.UTC().Format("20060102T150405.000"), journalExt))
+	if err := os.Rename(w.currentPath(), rotated); err != nil {
+		return fmt.Errorf("failed rotating journal: %w", err)
+	}
+	if err := w.open(); err != nil {
+		return err
+	}
+
+	if w.maxFiles <= 0 {
+		return nil
+	}
+	files, err := JournalFiles(w.dir)
+	if err != nil {
+		return err
+	}
+	// Last one is the current file
+	rotatedFiles := files[:len(files)-1]
+	for len(rotatedFiles) > w.maxFiles {
+		if err := os.Remove(rotatedFiles[0]); err != nil {
+			return fmt.Errorf("failed removing old journal: %w", err)
+		}
+		rotatedFiles = rotatedFiles[1:]
+	}
+	return nil
+}
+
+func (w *JournalWriter) Close() error {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	if w.file == nil {
+		return nil
+	}
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
+
+// Journal files of the dir, oldest first
+func JournalFiles(dir string) ([]string, error) {
+	rotated, err := filepath.Glob(filepath.Join(dir, journalPrefix+"-*"+journalExt))
+	if err != nil {
+		return nil, err
+	}
+	// Rotation times sort as strings
+	sort.Strings(rotated)
+
+	current := filepath.Join(dir, journalPrefix+journalExt)
+	if _, err := os.Stat(current); err == nil {
+		rotated = append(rotated, current)
+	}
+	return rotated, nil
+}
+
+// Read all the records of a journal
+func ReadJournal(r io.Reader) ([]JournalRecord, error) {
+	var res []JournalRecord
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var record JournalRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("invalid record line %d: %w", line, err)
+		}
+		res = append(res, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed reading journal: %w", err)
+	}
+	return res, nil
+}
+
+type ReplayStats struct {
+	Replayed int
+	// Records of unknown topics or with a payload that can't be decoded
+	Skipped int
+}
+
+// Publish the records on the bus, with their payload decoded into the type of their topic.
+//
+// The delay between two records is divided by speed, a zero speed publishes them as fast as possible
+func Replay(ctx context.Context, b *Bus, records []JournalRecord, speed float64) (ReplayStats, error) {
+	var stats ReplayStats
+
+	for i, record := range records {
+		if speed > 0 && i > 0 {
+			if wait := time.Duration(float64(record.Time.Sub(records[i-1].Time)) / speed); wait > 0 {
+				select {
+				case <-ctx.Done():
+					return stats, ctx.Err()
+				case <-time.After(wait):
+				}
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			return stats, err
+		}
+
+		payload, err := DecodePayload(record.Name, record.Payload)
+		if err != nil {
+			stats.Skipped++
+			continue
+		}
+		b.Notify(Event{Name: record.Name, Payload: payload})
+		stats.Replayed++
+	}
+
+	return stats, nil
+}
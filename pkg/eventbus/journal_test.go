@@ -0,0 +1,72 @@
+package eventbus_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/erwanlbp/trading-bot/pkg/eventbus"
+)
+
+type tick struct {
+	Coin  string
+	Price int
+}
+
+func TestJournalReplay(t *testing.T) {
+	t.Parallel()
+
+	topic := eventbus.NewTopic[tick]("journal_test_tick")
+	dir := t.TempDir()
+
+	// Small max size to rotate after each record, only 2 rotated files kept
+	writer, err := eventbus.NewJournalWriter(dir, 10, 2)
+	require.NoError(t, err)
+
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		event := eventbus.Event{Name: topic.Name, Payload: tick{Coin: "BTC", Price: i}}
+		require.NoError(t, writer.Write(start.Add(time.Duration(i)*time.Second), event))
+	}
+	require.NoError(t, writer.Write(start.Add(5*time.Second), eventbus.Event{Name: "unknown_topic", Payload: "hello"}))
+	require.NoError(t, writer.Close())
+
+	files, err := eventbus.JournalFiles(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 3)
+	assert.Equal(t, filepath.Join(dir, "events.jsonl"), files[2])
+
+	var records []eventbus.JournalRecord
+	for _, file := range files {
+		f, err := os.Open(file)
+		require.NoError(t, err)
+		fileRecords, err := eventbus.ReadJournal(f)
+		f.Close()
+		require.NoError(t, err)
+		records = append(records, fileRecords...)
+	}
+	require.Len(t, records, 3)
+
+	bus := eventbus.NewEventBus()
+	sub := eventbus.SubscribeWith(bus, eventbus.SubscriptionOptions{BufferSize: 10, Policy: eventbus.Block}, topic)
+
+	stats, err := eventbus.Replay(context.Background(), bus, records, 0)
+	require.NoError(t, err)
+	assert.Equal(t, eventbus.ReplayStats{Replayed: 2, Skipped: 1}, stats)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var got []tick
+	sub.Handler(ctx, func(_ context.Context, p tick) {
+		got = append(got, p)
+		if len(got) == 2 {
+			cancel()
+		}
+	})
+
+	assert.Equal(t, []tick{{Coin: "BTC", Price: 3}, {Coin: "BTC", Price: 4}}, got)
+}
@@ -1,39 +1,251 @@
 package eventbus
 
-import "context"
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// What to do with a new event when the subscription buffer is full
+type OverflowPolicy int
+
+const (
+	// Wait for the subscriber to make room, the publisher is blocked meanwhile
+	Block OverflowPolicy = iota
+	// Drop the oldest pending event to make room
+	DropOldest
+	// Drop the new event
+	DropNewest
+	// Replace the pending event with the same name, even if the buffer isn't full. If there's none, drop the oldest
+	Coalesce
+)
+
+func (p OverflowPolicy) String() string {
+	switch p {
+	case Block:
+		return "block"
+	case DropOldest:
+		return "drop_oldest"
+	case DropNewest:
+		return "drop_newest"
+	case Coalesce:
+		return "coalesce"
+	}
+	return "unknown"
+}
+
+type SubscriptionOptions struct {
+	// Max number of events waiting to be handled
+	BufferSize int
+	Policy     OverflowPolicy
+}
+
+var DefaultSubscriptionOptions = SubscriptionOptions{
+	BufferSize: 16,
+	Policy:     Block,
+}
+
+// Only the last event matters, the pending one is replaced while the handler is busy
+var LatestOnly = SubscriptionOptions{
+	BufferSize: 1,
+	Policy:     Coalesce,
+}
+
+type SubscriptionStats struct {
+	Events    []string
+	Policy    OverflowPolicy
+	Delivered uint64
+	Dropped   uint64
+	Pending   int
+}
 
 type Subscription struct {
-	EventsCh         chan Event
-	EventsSubscribed map[string]bool
-	closed           bool
+	bus              *Bus
+	eventsSubscribed map[string]bool
+	allEvents        bool
+	options          SubscriptionOptions
+
+	mtx   sync.Mutex
+	queue []Event
+	// Signaled when an event is queued
+	notEmpty chan struct{}
+	// Closed (and replaced) when an event is taken out of the queue, to wake up all blocked publishers
+	notFull chan struct{}
+
+	done      chan struct{}
+	closeOnce sync.Once
+
+	delivered atomic.Uint64
+	dropped   atomic.Uint64
 }
 
 type EventHandler func(context.Context, Event)
 
-func newSubscription(events []string) *Subscription {
+func newSubscription(bus *Bus, events []string, options SubscriptionOptions) *Subscription {
 	eventsMap := make(map[string]bool)
 	for _, event := range events {
 		eventsMap[event] = true
 	}
+	if options.BufferSize <= 0 {
+		options.BufferSize = DefaultSubscriptionOptions.BufferSize
+	}
 	return &Subscription{
-		EventsCh:         make(chan Event),
-		EventsSubscribed: eventsMap,
+		bus:              bus,
+		eventsSubscribed: eventsMap,
+		options:          options,
+		notEmpty:         make(chan struct{}, 1),
+		notFull:          make(chan struct{}),
+		done:             make(chan struct{}),
 	}
 }
 
+func (s *Subscription) IsSubscribed(event string) bool {
+	return s.allEvents || s.eventsSubscribed[event]
+}
+
+// Unsubscribe from the bus and stop the handler, pending events are dropped
 func (s *Subscription) Close() {
-	s.closed = true
-	close(s.EventsCh)
+	if s.stop() {
+		s.bus.unsubscribe(s)
+	}
+}
+
+// Return true the first time only
+func (s *Subscription) stop() bool {
+	stopped := false
+	s.closeOnce.Do(func() {
+		close(s.done)
+		stopped = true
+	})
+	return stopped
+}
+
+func (s *Subscription) Stats() SubscriptionStats {
+	s.mtx.Lock()
+	pending := len(s.queue)
+	s.mtx.Unlock()
+
+	return SubscriptionStats{
+		Events:    keys(s.eventsSubscribed),
+		Policy:    s.options.Policy,
+		Delivered: s.delivered.Load(),
+		Dropped:   s.dropped.Load(),
+		Pending:   pending,
+	}
+}
+
+// Queue the event following the overflow policy, only blocks with the Block policy
+func (s *Subscription) push(event Event) {
+	for {
+		select {
+		case <-s.done:
+			return
+		default:
+		}
+
+		s.mtx.Lock()
+		if s.options.Policy == Coalesce {
+			if i := s.indexOf(event.Name); i >= 0 {
+				s.queue[i] = event
+				s.mtx.Unlock()
+				s.dropped.Add(1)
+				return
+			}
+		}
+
+		if len(s.queue) >= s.options.BufferSize {
+			switch s.options.Policy {
+			case Block:
+				notFull := s.notFull
+				s.mtx.Unlock()
+				select {
+				case <-notFull:
+				case <-s.done:
+				}
+				continue
+			case DropNewest:
+				s.mtx.Unlock()
+				s.dropped.Add(1)
+				return
+			case DropOldest, Coalesce:
+				s.queue = s.queue[1:]
+				s.dropped.Add(1)
+			}
+		}
+
+		s.queue = append(s.queue, event)
+		s.mtx.Unlock()
+		signal(s.notEmpty)
+		return
+	}
 }
 
-// If events is not provided, will be all events for this subscription
+func (s *Subscription) pop() (Event, bool) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if len(s.queue) == 0 {
+		return Event{}, false
+	}
+	event := s.queue[0]
+	s.queue = s.queue[1:]
+	close(s.notFull)
+	s.notFull = make(chan struct{})
+	return event, true
+}
+
+func (s *Subscription) indexOf(name string) int {
+	for i, e := range s.queue {
+		if e.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// Handle the events one at a time, until the context is canceled or the subscription closed.
+//
+// The subscription is closed when it returns, so publishers don't wait for a handler that's gone
 func (s *Subscription) Handler(ctx context.Context, handler EventHandler) {
+	defer s.Close()
+
 	for {
 		select {
-		case ev := <-s.EventsCh:
-			go handler(ctx, ev)
+		case <-s.done:
+			return
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		if event, ok := s.pop(); ok {
+			handler(ctx, event)
+			s.delivered.Add(1)
+			continue
+		}
+
+		select {
+		case <-s.notEmpty:
+		case <-s.done:
+			return
 		case <-ctx.Done():
-			break
+			return
 		}
 	}
 }
+
+// Non blocking, a pending signal is enough to wake up the waiter
+func signal(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
+
+func keys(m map[string]bool) []string {
+	var res []string
+	for k := range m {
+		res = append(res, k)
+	}
+	return res
+}
@@ -0,0 +1,68 @@
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// Event name bound to its payload type, so publishers and subscribers can't disagree on it
+type Topic[T any] struct {
+	Name string
+}
+
+func NewTopic[T any](name string) Topic[T] {
+	registry.Store(name, func(raw json.RawMessage) (interface{}, error) {
+		var payload T
+		err := json.Unmarshal(raw, &payload)
+		return payload, err
+	})
+	return Topic[T]{Name: name}
+}
+
+// Payload decoders of all the topics, by name, to rebuild typed events from a journal
+var registry sync.Map
+
+// Decode the JSON payload of an event into the payload type of its topic
+func DecodePayload(name string, raw json.RawMessage) (interface{}, error) {
+	decoder, ok := registry.Load(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown topic '%s'", name)
+	}
+	return decoder.(func(json.RawMessage) (interface{}, error))(raw)
+}
+
+// Payload of the events that only signal something happened
+type NoPayload struct{}
+
+func Publish[T any](b *Bus, topic Topic[T], payload T) {
+	b.Notify(Event{Name: topic.Name, Payload: payload})
+}
+
+type TypedSubscription[T any] struct {
+	*Subscription
+}
+
+type TypedHandler[T any] func(context.Context, T)
+
+func Subscribe[T any](b *Bus, topic Topic[T]) TypedSubscription[T] {
+	return SubscribeWith(b, DefaultSubscriptionOptions, topic)
+}
+
+func SubscribeWith[T any](b *Bus, options SubscriptionOptions, topic Topic[T]) TypedSubscription[T] {
+	return TypedSubscription[T]{Subscription: b.SubscribeWith(options, topic.Name)}
+}
+
+// Same as Subscription.Handler, with the payload already typed
+func (s TypedSubscription[T]) Handler(ctx context.Context, handler TypedHandler[T]) {
+	s.Subscription.Handler(ctx, func(ctx context.Context, e Event) {
+		// Only possible if published with Bus.Notify, bypassing the topic
+		payload, ok := e.Payload.(T)
+		if !ok {
+			s.dropped.Add(1)
+			return
+		}
+		handler(ctx, payload)
+	})
+}
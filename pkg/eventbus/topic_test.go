@@ -0,0 +1,41 @@
+package eventbus_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/erwanlbp/trading-bot/pkg/eventbus"
+)
+
+type price struct {
+	Coin  string
+	Price int
+}
+
+func TestTypedTopic(t *testing.T) {
+	t.Parallel()
+
+	bus := eventbus.NewEventBus()
+	topic := eventbus.NewTopic[price]("price")
+
+	sub := eventbus.Subscribe(bus, topic)
+
+	eventbus.Publish(bus, topic, price{Coin: "BTC", Price: 10})
+	// Bypassing the topic with a wrong payload, it must not reach the handler
+	bus.Notify(eventbus.Event{Name: topic.Name, Payload: "BTC"})
+	eventbus.Publish(bus, topic, price{Coin: "ETH", Price: 2})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var got []price
+	sub.Handler(ctx, func(_ context.Context, p price) {
+		got = append(got, p)
+		if len(got) == 2 {
+			cancel()
+		}
+	})
+
+	assert.Equal(t, []price{{Coin: "BTC", Price: 10}, {Coin: "ETH", Price: 2}}, got)
+	assert.Equal(t, uint64(1), sub.Stats().Dropped)
+}
@@ -4,6 +4,8 @@ import (
 	"time"
 
 	"github.com/shopspring/decimal"
+
+	"github.com/erwanlbp/trading-bot/pkg/constant"
 )
 
 const BalanceHistoryTableName = "balance_history"
@@ -12,8 +14,24 @@ type BalanceHistory struct {
 	Timestamp   time.Time `gorm:"primaryKey"`
 	BtcBalance  decimal.Decimal
 	UsdtBalance decimal.Decimal
+	// Value in the bridge configured when it was saved
+	Bridge        string
+	BridgeBalance decimal.Decimal
 }
 
 func (BalanceHistory) TableName() string {
 	return BalanceHistoryTableName
 }
+
+// Saved value in the coin, zero if it wasn't saved in that coin
+func (b BalanceHistory) ValueIn(coin string) decimal.Decimal {
+	switch {
+	case coin == constant.USDT:
+		return b.UsdtBalance
+	case coin == constant.BTC:
+		return b.BtcBalance
+	case coin == b.Bridge:
+		return b.BridgeBalance
+	}
+	return decimal.Zero
+}
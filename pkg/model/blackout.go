@@ -0,0 +1,23 @@
+package model
+
+import (
+	"time"
+)
+
+const BlackoutTableName = "blackouts"
+
+// Range of time around a known event (FOMC, token unlock...) where jumps are not done
+type Blackout struct {
+	ID       uint `gorm:"primaryKey"`
+	StartsAt time.Time
+	EndsAt   time.Time
+	Reason   string
+}
+
+func (Blackout) TableName() string {
+	return BlackoutTableName
+}
+
+func (b Blackout) Contains(t time.Time) bool {
+	return !t.Before(b.StartsAt) && t.Before(b.EndsAt)
+}
@@ -0,0 +1,25 @@
+package model
+
+import (
+	"time"
+)
+
+const CircuitBreakerTableName = "circuit_breaker"
+
+// Single line table, state of the risk circuit breaker
+type CircuitBreaker struct {
+	ID uint `gorm:"primaryKey"`
+
+	// When tripped, the jump finder is halted until a manual resume
+	Tripped   bool
+	Reason    string
+	TrippedOn time.Time
+	// Limits are measured from this date, so that a resume doesn't trip again on the same loss
+	ResumedOn time.Time
+
+	ConsecutiveFailedJumps int
+}
+
+func (CircuitBreaker) TableName() string {
+	return CircuitBreakerTableName
+}
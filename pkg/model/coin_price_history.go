@@ -13,7 +13,23 @@ type CoinPrice struct {
 	AltCoin   string    `gorm:"primaryKey"`
 	Timestamp time.Time `gorm:"primaryKey"`
 	Price     decimal.Decimal
-	Averaged  bool
+	// Best bid/ask from the book ticker, 0 if it couldn't be fetched
+	BidPrice decimal.Decimal `gorm:"default:0"`
+	AskPrice decimal.Decimal `gorm:"default:0"`
+	// Downsampled row: Price and BidPrice/AskPrice are averages over the bucket
+	Averaged bool
+
+	// Bucket size of a downsampled row, 0 for a raw price
+	Resolution time.Duration `gorm:"default:0"`
+	// Number of raw prices in the bucket, 0 for a raw price
+	Samples int64 `gorm:"default:0"`
+	// Zero for a raw price, use Min(), Max() and Last()
+	MinPrice  decimal.Decimal `gorm:"default:0"`
+	MaxPrice  decimal.Decimal `gorm:"default:0"`
+	LastPrice decimal.Decimal `gorm:"default:0"`
+
+	// Built from Binance klines by a backfill, not collected by the bot
+	Backfilled bool `gorm:"default:false"`
 
 	CoinRef Coin `gorm:"foreignKey:Coin;references:Coin"`
 }
@@ -21,3 +37,31 @@ type CoinPrice struct {
 func (CoinPrice) TableName() string {
 	return CoinPriceTableName
 }
+
+// Price we would get selling the coin, falls back on last price if we don't have the book ticker
+func (c CoinPrice) SellPrice() decimal.Decimal {
+	if c.BidPrice.IsPositive() {
+		return c.BidPrice
+	}
+	return c.Price
+}
+
+// Price we would pay buying the coin, falls back on last price if we don't have the book ticker
+func (c CoinPrice) BuyPrice() decimal.Decimal {
+	if c.AskPrice.IsPositive() {
+		return c.AskPrice
+	}
+	return c.Price
+}
+
+func (c CoinPrice) Min() decimal.Decimal {
+	return rawOr(c.Resolution, c.Price, c.MinPrice)
+}
+
+func (c CoinPrice) Max() decimal.Decimal {
+	return rawOr(c.Resolution, c.Price, c.MaxPrice)
+}
+
+func (c CoinPrice) Last() decimal.Decimal {
+	return rawOr(c.Resolution, c.Price, c.LastPrice)
+}
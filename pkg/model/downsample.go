@@ -0,0 +1,152 @@
+package model
+
+import (
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// A raw row only has its value
+func rawOr(resolution time.Duration, value, aggregated decimal.Decimal) decimal.Decimal {
+	if resolution == 0 {
+		return value
+	}
+	return aggregated
+}
+
+// Number of raw values behind a row
+func weight(samples int64) int64 {
+	if samples <= 0 {
+		return 1
+	}
+	return samples
+}
+
+// Stats of a value over a bucket, averages are weighted by the number of raw values behind each row
+type bucketStat struct {
+	weight   int64
+	sum      decimal.Decimal
+	min, max decimal.Decimal
+	last     decimal.Decimal
+}
+
+// Rows must be added by increasing timestamp
+func (s *bucketStat) add(samples int64, avg, min, max, last decimal.Decimal) {
+	w := weight(samples)
+	if s.weight == 0 || min.LessThan(s.min) {
+		s.min = min
+	}
+	if s.weight == 0 || max.GreaterThan(s.max) {
+		s.max = max
+	}
+	s.weight += w
+	s.sum = s.sum.Add(avg.Mul(decimal.NewFromInt(w)))
+	s.last = last
+}
+
+func (s bucketStat) avg() decimal.Decimal {
+	if s.weight == 0 {
+		return decimal.Zero
+	}
+	return s.sum.Div(decimal.NewFromInt(s.weight))
+}
+
+// Aggregate the prices of a coin into buckets of the resolution, with min/max/avg/last of each bucket.
+//
+// Rows can be raw or downsampled ones of a finer resolution
+func DownsampleCoinPrices(rows []CoinPrice, resolution time.Duration) []CoinPrice {
+	sort.Slice(rows, func(i, j int) bool { return rows[i].Timestamp.Before(rows[j].Timestamp) })
+
+	var res []CoinPrice
+	var price, bid, ask bucketStat
+	flush := func() {
+		bucket := &res[len(res)-1]
+		bucket.Price = price.avg()
+		bucket.MinPrice = price.min
+		bucket.MaxPrice = price.max
+		bucket.LastPrice = price.last
+		bucket.Samples = price.weight
+		bucket.BidPrice = bid.avg()
+		bucket.AskPrice = ask.avg()
+	}
+
+	for _, row := range rows {
+		start := row.Timestamp.Truncate(resolution)
+		if len(res) == 0 || !res[len(res)-1].Timestamp.Equal(start) {
+			if len(res) > 0 {
+				flush()
+			}
+			res = append(res, CoinPrice{
+				Coin:       row.Coin,
+				AltCoin:    row.AltCoin,
+				Timestamp:  start,
+				Averaged:   true,
+				Resolution: resolution,
+				Backfilled: true,
+			})
+			price, bid, ask = bucketStat{}, bucketStat{}, bucketStat{}
+		}
+
+		// A bucket is backfilled only if all its rows are
+		res[len(res)-1].Backfilled = res[len(res)-1].Backfilled && row.Backfilled
+
+		price.add(row.Samples, row.Price, row.Min(), row.Max(), row.Last())
+		// Book ticker prices are 0 when they couldn't be fetched
+		if row.BidPrice.IsPositive() {
+			bid.add(row.Samples, row.BidPrice, row.BidPrice, row.BidPrice, row.BidPrice)
+		}
+		if row.AskPrice.IsPositive() {
+			ask.add(row.Samples, row.AskPrice, row.AskPrice, row.AskPrice, row.AskPrice)
+		}
+	}
+	if len(res) > 0 {
+		flush()
+	}
+
+	return res
+}
+
+// Aggregate the ratios of a pair into buckets of the resolution, with min/max/avg/last of each bucket.
+//
+// Rows can be raw or downsampled ones of a finer resolution
+func DownsamplePairHistory(rows []PairHistory, resolution time.Duration) []PairHistory {
+	sort.Slice(rows, func(i, j int) bool { return rows[i].Timestamp.Before(rows[j].Timestamp) })
+
+	var res []PairHistory
+	var ratio bucketStat
+	flush := func() {
+		bucket := &res[len(res)-1]
+		bucket.Ratio = ratio.avg()
+		bucket.MinRatio = ratio.min
+		bucket.MaxRatio = ratio.max
+		bucket.LastRatio = ratio.last
+		bucket.Samples = ratio.weight
+	}
+
+	for _, row := range rows {
+		start := row.Timestamp.Truncate(resolution)
+		if len(res) == 0 || !res[len(res)-1].Timestamp.Equal(start) {
+			if len(res) > 0 {
+				flush()
+			}
+			res = append(res, PairHistory{
+				PairID:     row.PairID,
+				Timestamp:  start,
+				Averaged:   true,
+				Resolution: resolution,
+				Backfilled: true,
+			})
+			ratio = bucketStat{}
+		}
+
+		res[len(res)-1].Backfilled = res[len(res)-1].Backfilled && row.Backfilled
+
+		ratio.add(row.Samples, row.Ratio, row.Min(), row.Max(), row.Last())
+	}
+	if len(res) > 0 {
+		flush()
+	}
+
+	return res
+}
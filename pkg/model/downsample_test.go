@@ -0,0 +1,45 @@
+package model_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/erwanlbp/trading-bot/pkg/model"
+)
+
+func TestDownsamplePairHistory(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	raw := func(minutes int, ratio int64) model.PairHistory {
+		return model.PairHistory{PairID: 1, Timestamp: start.Add(time.Duration(minutes) * time.Minute), Ratio: decimal.NewFromInt(ratio)}
+	}
+
+	// 5 minutes buckets: [0, 5) has 3 raw ratios, [5, 10) has 1
+	fiveMinutes := model.DownsamplePairHistory([]model.PairHistory{raw(6, 8), raw(0, 4), raw(4, 2), raw(2, 6)}, 5*time.Minute)
+	if assert.Len(t, fiveMinutes, 2) {
+		b := fiveMinutes[0]
+		assert.Equal(t, start, b.Timestamp)
+		assert.Equal(t, 5*time.Minute, b.Resolution)
+		assert.EqualValues(t, 3, b.Samples)
+		assert.Equal(t, "4", b.Ratio.String())
+		assert.Equal(t, "2", b.Min().String())
+		assert.Equal(t, "6", b.Max().String())
+		assert.Equal(t, "2", b.Last().String())
+		assert.True(t, b.Averaged)
+	}
+
+	// Hourly bucket from the 5 minutes ones and a late raw ratio, the average is weighted by the raw ratios behind each row
+	hourly := model.DownsamplePairHistory(append(fiveMinutes, raw(50, 20)), time.Hour)
+	if assert.Len(t, hourly, 1) {
+		b := hourly[0]
+		assert.EqualValues(t, 5, b.Samples)
+		assert.Equal(t, "8", b.Ratio.String())
+		assert.Equal(t, "2", b.Min().String())
+		assert.Equal(t, "20", b.Max().String())
+		assert.Equal(t, "20", b.Last().String())
+	}
+}
@@ -0,0 +1,101 @@
+package model
+
+import (
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/erwanlbp/trading-bot/pkg/util"
+)
+
+// Time spent on a coin, between the jump to it and the jump from it
+type Holding struct {
+	Coin      string
+	EnteredOn time.Time
+	ExitedOn  time.Time
+	// Bridge prices of the coin
+	EntryPrice decimal.Decimal
+	ExitPrice  decimal.Decimal
+	// Average evolution (in %) of the other coins during the holding, zero if unknown
+	MarketResult decimal.Decimal
+}
+
+// Evolution (in %) of the coin value in bridge during the holding
+func (h Holding) Result() decimal.Decimal {
+	if !h.EntryPrice.IsPositive() {
+		return decimal.Zero
+	}
+	return h.ExitPrice.Div(h.EntryPrice).Sub(decimal.NewFromInt(1)).Mul(decimal.NewFromInt(100))
+}
+
+// Evolution (in %) of the coin value compared to the other coins during the holding, a coin falling with the whole market didn't lose anything
+func (h Holding) RelativeResult() decimal.Decimal {
+	hundred := decimal.NewFromInt(100)
+	market := hundred.Add(h.MarketResult)
+	if !market.IsPositive() {
+		return h.Result()
+	}
+	return hundred.Add(h.Result()).Div(market).Sub(decimal.NewFromInt(1)).Mul(hundred)
+}
+
+// Completed holdings, oldest first. The bridge is not a holding.
+//
+// Only jumps following each other make a holding, moves not saved as jumps (like take profits) leave a gap
+func Holdings(jumps []Jump, bridge string) []Holding {
+	sorted := append([]Jump{}, jumps...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Timestamp.Before(sorted[j].Timestamp) })
+
+	var res []Holding
+	for i := 1; i < len(sorted); i++ {
+		entry, exit := sorted[i-1], sorted[i]
+		if entry.ToCoin != exit.FromCoin || entry.ToCoin == bridge {
+			continue
+		}
+		res = append(res, Holding{
+			Coin:       entry.ToCoin,
+			EnteredOn:  entry.Timestamp,
+			ExitedOn:   exit.Timestamp,
+			EntryPrice: entry.ToPrice,
+			ExitPrice:  exit.FromPrice,
+		})
+	}
+	return res
+}
+
+// Last n holdings (at most) entered after since, oldest first
+func LastHoldings(holdings []Holding, since time.Time, n int) []Holding {
+	res := util.FilterSlice(holdings, func(h Holding) bool { return !h.EnteredOn.Before(since) })
+	if len(res) > n {
+		res = res[len(res)-n:]
+	}
+	return res
+}
+
+// Average result (in %) of the holdings, relative to the other coins
+func AvgResult(holdings []Holding) decimal.Decimal {
+	if len(holdings) == 0 {
+		return decimal.Zero
+	}
+	sum := decimal.Zero
+	for _, h := range holdings {
+		sum = sum.Add(h.RelativeResult())
+	}
+	return sum.Div(decimal.NewFromInt(int64(len(holdings))))
+}
+
+const CoinSuspensionTableName = "coin_suspensions"
+
+// Last time a coin was disabled for its bad results, one line per coin
+type CoinSuspension struct {
+	Coin        string `gorm:"primaryKey"`
+	Active      bool
+	SuspendedOn time.Time
+	// Holdings before this date are not counted anymore, to not suspend the coin again for the same results
+	Until  time.Time
+	Reason string
+}
+
+func (CoinSuspension) TableName() string {
+	return CoinSuspensionTableName
+}
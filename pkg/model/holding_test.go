@@ -0,0 +1,50 @@
+package model_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/erwanlbp/trading-bot/pkg/model"
+)
+
+func TestHoldings(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	jump := func(hours int, from, to string, fromPrice, toPrice int64) model.Jump {
+		return model.Jump{
+			FromCoin:  from,
+			ToCoin:    to,
+			Timestamp: start.Add(time.Duration(hours) * time.Hour),
+			FromPrice: decimal.NewFromInt(fromPrice),
+			ToPrice:   decimal.NewFromInt(toPrice),
+		}
+	}
+
+	holdings := model.Holdings([]model.Jump{
+		jump(3, "BBB", "USDT", 90, 1),
+		jump(0, "AAA", "BBB", 10, 100),
+		jump(5, "USDT", "CCC", 1, 50),
+		jump(6, "DDD", "AAA", 5, 20),
+	}, "USDT")
+
+	// Bridge is not a holding, and CCC → DDD is missing
+	if assert.Len(t, holdings, 1) {
+		assert.Equal(t, "BBB", holdings[0].Coin)
+		assert.Equal(t, "-10", holdings[0].Result().String())
+	}
+
+	last := model.LastHoldings([]model.Holding{
+		{Coin: "AAA", EnteredOn: start, EntryPrice: decimal.NewFromInt(10), ExitPrice: decimal.NewFromInt(9)},
+		{Coin: "AAA", EnteredOn: start.Add(time.Hour), EntryPrice: decimal.NewFromInt(10), ExitPrice: decimal.NewFromInt(11)},
+		{Coin: "AAA", EnteredOn: start.Add(2 * time.Hour), EntryPrice: decimal.NewFromInt(10), ExitPrice: decimal.NewFromInt(8)},
+	}, start.Add(time.Minute), 5)
+	assert.Len(t, last, 2, "holdings before the date are ignored")
+	assert.Equal(t, "-5", model.AvgResult(last).String())
+
+	falling := model.Holding{EntryPrice: decimal.NewFromInt(10), ExitPrice: decimal.NewFromInt(9), MarketResult: decimal.NewFromInt(-20)}
+	assert.Equal(t, "12.5", falling.RelativeResult().String(), "lost less than the other coins")
+}
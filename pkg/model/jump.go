@@ -18,6 +18,13 @@ type Jump struct {
 	ToQuantity   decimal.Decimal
 	ToPrice      decimal.Decimal
 
+	// Ratio (between 0 and 1) of value lost walking the order books, compared to best bid/ask at decision time
+	ExpectedSlippage decimal.Decimal `gorm:"default:0"`
+	RealizedSlippage decimal.Decimal `gorm:"default:0"`
+
+	// Asked on telegram, not decided by the bot. Exits and entries from the bridge are manual trades
+	Manual bool `gorm:"default:false"`
+
 	FromCoinRef Coin `gorm:"foreignKey:FromCoin;references:Coin"`
 	ToCoinRef   Coin `gorm:"foreignKey:ToCoin;references:Coin"`
 }
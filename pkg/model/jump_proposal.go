@@ -0,0 +1,55 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const JumpProposalTableName = "jump_proposals"
+
+type JumpProposalStatus string
+
+const (
+	JumpProposalPending  JumpProposalStatus = "pending"
+	JumpProposalExecuted JumpProposalStatus = "executed"
+	JumpProposalFailed   JumpProposalStatus = "failed"
+	JumpProposalRejected JumpProposalStatus = "rejected"
+	JumpProposalExpired  JumpProposalStatus = "expired"
+	// Approved, but the diff didn't hold anymore when re-checked
+	JumpProposalOutdated JumpProposalStatus = "outdated"
+)
+
+// Jump found in semi-automatic mode, waiting for an approval on telegram
+type JumpProposal struct {
+	ID        uint `gorm:"primaryKey"`
+	CreatedOn time.Time
+	// Coins of the path, comma separated, from current coin to target coin
+	Path string
+
+	Diff       decimal.Decimal
+	NeededDiff decimal.Decimal
+	// Fee multiplier of the whole path
+	Fee decimal.Decimal
+
+	FromQuantity       decimal.Decimal
+	ExpectedToQuantity decimal.Decimal
+
+	Status    JumpProposalStatus
+	DecidedOn time.Time
+	// Diff found when re-checking after approval
+	RecheckDiff decimal.Decimal `gorm:"default:0"`
+}
+
+func (JumpProposal) TableName() string {
+	return JumpProposalTableName
+}
+
+func (p JumpProposal) Coins() []string {
+	return strings.Split(p.Path, ",")
+}
+
+func (p JumpProposal) LogSymbol() string {
+	return strings.Join(p.Coins(), " → ")
+}
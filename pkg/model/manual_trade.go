@@ -0,0 +1,34 @@
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const ManualTradeTableName = "manual_trades"
+
+const (
+	// Current coin sold to the bridge
+	ManualExit = "exit"
+	// Coin bought from the bridge
+	ManualEntry = "entry"
+)
+
+// Trade between a coin and the bridge asked on telegram. Not a jump, the bridge isn't one of the coins
+type ManualTrade struct {
+	ID        uint `gorm:"primaryKey"`
+	Timestamp time.Time
+	Side      string
+	Coin      string
+	Bridge    string
+
+	Price    decimal.Decimal
+	Quantity decimal.Decimal
+	// Bridge received on exit, spent on entry
+	BridgeQuantity decimal.Decimal
+}
+
+func (ManualTrade) TableName() string {
+	return ManualTradeTableName
+}
@@ -36,7 +36,23 @@ type PairHistory struct {
 	PairID    uint      `gorm:"primaryKey"`
 	Timestamp time.Time `gorm:"primaryKey"`
 	Ratio     decimal.Decimal
-	Averaged  bool
+	// Downsampled row: Ratio is the average over the bucket
+	Averaged bool
+
+	// Bucket size of a downsampled row, 0 for a raw ratio
+	Resolution time.Duration `gorm:"default:0"`
+	// Number of raw ratios in the bucket, 0 for a raw ratio
+	Samples int64 `gorm:"default:0"`
+	// Zero for a raw ratio, use Min(), Max() and Last()
+	MinRatio  decimal.Decimal `gorm:"default:0"`
+	MaxRatio  decimal.Decimal `gorm:"default:0"`
+	LastRatio decimal.Decimal `gorm:"default:0"`
+
+	// Derived from backfilled prices, not collected by the bot
+	Backfilled bool `gorm:"default:false"`
+
+	// One of the prices had an anomaly when it was saved, the ratio may be off
+	Anomaly bool `gorm:"default:false"`
 
 	Pair Pair `gorm:"foreignKey:PairID;references:ID"`
 }
@@ -45,9 +61,31 @@ func (PairHistory) TableName() string {
 	return PairHistoryTableName
 }
 
+func (p PairHistory) Min() decimal.Decimal {
+	return rawOr(p.Resolution, p.Ratio, p.MinRatio)
+}
+
+func (p PairHistory) Max() decimal.Decimal {
+	return rawOr(p.Resolution, p.Ratio, p.MaxRatio)
+}
+
+func (p PairHistory) Last() decimal.Decimal {
+	return rawOr(p.Resolution, p.Ratio, p.LastRatio)
+}
+
 // TODO Why does this struct is not just PairHistory ?
 type PairWithTickerRatio struct {
-	Pair      Pair
-	Ratio     decimal.Decimal
-	Timestamp time.Time
+	Pair  Pair
+	Ratio decimal.Decimal
+	// Ratio we'd really get jumping now : selling from_coin at bid, buying to_coin at ask
+	ExecutableRatio decimal.Decimal
+	Timestamp       time.Time
+}
+
+// Convert a ratio of last prices to the bid/ask basis of ExecutableRatio, with the current spread
+func (p PairWithTickerRatio) WithSpread(ratio decimal.Decimal) decimal.Decimal {
+	if p.Ratio.IsZero() {
+		return ratio
+	}
+	return ratio.Mul(p.ExecutableRatio).Div(p.Ratio)
 }
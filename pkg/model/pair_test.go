@@ -0,0 +1,24 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/erwanlbp/trading-bot/pkg/model"
+)
+
+func TestPairWithTickerRatioWithSpread(t *testing.T) {
+	t.Parallel()
+
+	pair := model.PairWithTickerRatio{
+		Ratio:           decimal.RequireFromString("0.05"),
+		ExecutableRatio: decimal.RequireFromString("0.049"),
+	}
+	// Same spread as now: a jump ratio equal to the current one gives a diff of exactly 1
+	assert.Equal(t, "0.049", pair.WithSpread(decimal.RequireFromString("0.05")).String())
+	assert.True(t, pair.ExecutableRatio.Div(pair.WithSpread(pair.Ratio)).Equal(decimal.NewFromInt(1)))
+
+	assert.Equal(t, "0.05", model.PairWithTickerRatio{}.WithSpread(decimal.RequireFromString("0.05")).String())
+}
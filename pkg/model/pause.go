@@ -0,0 +1,40 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+const PauseTableName = "pause"
+
+// Single line table, jumps are paused from telegram until resumed or until the end date
+type Pause struct {
+	ID       uint `gorm:"primaryKey"`
+	Paused   bool
+	Reason   string
+	PausedOn time.Time
+	// Zero if paused until a manual resume
+	Until        time.Time
+	LastReminder time.Time
+}
+
+func (Pause) TableName() string {
+	return PauseTableName
+}
+
+func (p Pause) IsActive(now time.Time) bool {
+	return p.Paused && (p.Until.IsZero() || now.Before(p.Until))
+}
+
+func (p Pause) Description() string {
+	res := fmt.Sprintf("jumps are paused since %s", p.PausedOn.Format(time.DateTime))
+	if !p.Until.IsZero() {
+		res += fmt.Sprintf(" until %s", p.Until.Format(time.DateTime))
+	} else {
+		res += ", send /unpause to continue"
+	}
+	if p.Reason != "" {
+		res += fmt.Sprintf(" (%s)", p.Reason)
+	}
+	return res
+}
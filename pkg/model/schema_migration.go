@@ -0,0 +1,20 @@
+package model
+
+import (
+	"time"
+)
+
+const SchemaMigrationTableName = "schema_migrations"
+
+// A migration applied on the DB
+type SchemaMigration struct {
+	Version   uint `gorm:"primaryKey;autoIncrement:false"`
+	Name      string
+	AppliedAt time.Time
+	// Recorded without running it, the DB already had its changes
+	Detected bool
+}
+
+func (SchemaMigration) TableName() string {
+	return SchemaMigrationTableName
+}
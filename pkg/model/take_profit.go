@@ -0,0 +1,65 @@
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const TakeProfitTableName = "take_profits"
+
+type TakeProfit struct {
+	Timestamp time.Time `gorm:"primaryKey"`
+	Coin      string
+
+	// Bridge value of the position at the reference point (last entry or last take profit), and when taking profit
+	ReferenceValue decimal.Decimal
+	Value          decimal.Decimal
+
+	SoldQuantity decimal.Decimal
+	SoldPrice    decimal.Decimal
+	// Bridge received, and the part of it that is profit compared to the reference
+	Proceeds       decimal.Decimal
+	RealizedProfit decimal.Decimal
+
+	// If true, proceeds are kept in the bridge and not spent on next buys
+	Banked bool
+}
+
+func (TakeProfit) TableName() string {
+	return TakeProfitTableName
+}
+
+const ReserveReleaseTableName = "reserve_releases"
+
+const (
+	// Released with /release_reserve
+	ReserveReleasedManually = "manual"
+	// The bridge balance got below the reserve, the missing part was withdrawn or spent by hand
+	ReserveReleasedCapped = "capped"
+)
+
+// Bridge amount taken out of the banked proceeds, so it can be spent buying coins again
+type ReserveRelease struct {
+	ID        uint `gorm:"primaryKey"`
+	Timestamp time.Time
+	Amount    decimal.Decimal
+	Reason    string
+}
+
+func (ReserveRelease) TableName() string {
+	return ReserveReleaseTableName
+}
+
+const PositionReferenceTableName = "position_reference"
+
+// Single line table, bridge value of the position when we last entered from the bridge or took profit
+type PositionReference struct {
+	ID        uint `gorm:"primaryKey"`
+	Timestamp time.Time
+	Value     decimal.Decimal
+}
+
+func (PositionReference) TableName() string {
+	return PositionReferenceTableName
+}
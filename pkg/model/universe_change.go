@@ -0,0 +1,19 @@
+package model
+
+import (
+	"time"
+)
+
+const UniverseChangeTableName = "universe_changes"
+
+// A coin added to or removed from the coin list by the universe manager
+type UniverseChange struct {
+	ID        uint `gorm:"primaryKey"`
+	Timestamp time.Time
+	Coin      string
+	Added     bool
+}
+
+func (UniverseChange) TableName() string {
+	return UniverseChangeTableName
+}
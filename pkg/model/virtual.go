@@ -0,0 +1,85 @@
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/erwanlbp/trading-bot/pkg/util"
+)
+
+// Virtual tables are used by strategies that decide jumps but never trade (shadow mode).
+// They mirror the real tables, with the strategy name in the primary key.
+
+const VirtualPortfolioTableName = "virtual_portfolio"
+
+type VirtualPortfolio struct {
+	Strategy string `gorm:"primaryKey"`
+	Coin     string
+	Quantity decimal.Decimal
+	LastJump time.Time
+
+	// Bridge value the strategy started with, to compute its performance
+	StartBalance decimal.Decimal
+	StartedOn    time.Time
+}
+
+func (VirtualPortfolio) TableName() string {
+	return VirtualPortfolioTableName
+}
+
+const VirtualJumpTableName = "virtual_jumps"
+
+type VirtualJump struct {
+	Strategy  string    `gorm:"primaryKey"`
+	FromCoin  string    `gorm:"primaryKey"`
+	ToCoin    string    `gorm:"primaryKey"`
+	Timestamp time.Time `gorm:"primaryKey"`
+
+	FromQuantity decimal.Decimal
+	FromPrice    decimal.Decimal
+	ToQuantity   decimal.Decimal
+	ToPrice      decimal.Decimal
+}
+
+func (VirtualJump) TableName() string {
+	return VirtualJumpTableName
+}
+
+const VirtualPairTableName = "virtual_pairs"
+
+type VirtualPair struct {
+	Strategy string `gorm:"primaryKey"`
+	FromCoin string `gorm:"primaryKey"`
+	ToCoin   string `gorm:"primaryKey"`
+
+	LastJumpRatio        decimal.Decimal
+	LastJumpRatioBasedOn time.Time
+}
+
+func (VirtualPair) TableName() string {
+	return VirtualPairTableName
+}
+
+func (p VirtualPair) LogSymbol() string {
+	return util.LogSymbol(p.FromCoin, p.ToCoin)
+}
+
+const VirtualDiffTableName = "virtual_diff"
+
+type VirtualDiff struct {
+	Strategy   string    `gorm:"primaryKey"`
+	FromCoin   string    `gorm:"primaryKey"`
+	ToCoin     string    `gorm:"primaryKey"`
+	Timestamp  time.Time `gorm:"primaryKey"`
+	Diff       decimal.Decimal
+	NeededDiff decimal.Decimal
+}
+
+func (VirtualDiff) TableName() string {
+	return VirtualDiffTableName
+}
+
+func (d *VirtualDiff) LogSymbol() string {
+	return util.LogSymbol(d.FromCoin, d.ToCoin)
+}
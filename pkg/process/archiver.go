@@ -0,0 +1,77 @@
+package process
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prprprus/scheduler"
+	"go.uber.org/zap"
+
+	"github.com/erwanlbp/trading-bot/pkg/archive"
+	"github.com/erwanlbp/trading-bot/pkg/config/configfile"
+	"github.com/erwanlbp/trading-bot/pkg/log"
+	"github.com/erwanlbp/trading-bot/pkg/repository"
+)
+
+// Move the old history out of the DB, to monthly files still read by the repository
+type Archiver struct {
+	Logger     *log.Logger
+	Repository *repository.Repository
+	ConfigFile *configfile.ConfigFile
+}
+
+func NewArchiver(l *log.Logger, r *repository.Repository, cf *configfile.ConfigFile) *Archiver {
+	return &Archiver{
+		Logger:     l,
+		Repository: r,
+		ConfigFile: cf,
+	}
+}
+
+func (p *Archiver) Start(ctx context.Context) {
+	if !p.ConfigFile.Archive.Enabled {
+		return
+	}
+
+	go func() {
+
+		Scheduler, _ := scheduler.NewScheduler(1000)
+
+		id := Scheduler.Every().Hour(4).Minute(0).Second(0).Do(p.Archive) // 6AM Europe/Paris
+
+		// If ctx is canceled, we'll stop the job
+		<-ctx.Done()
+
+		if err := Scheduler.CancelJob(id); err != nil {
+			p.Logger.Error("failed canceling job", zap.Error(err))
+		}
+	}()
+}
+
+func (p *Archiver) Archive() {
+	logger := p.Logger.With(zap.String("process", "archiver"))
+
+	// Only whole months, so a partition is written once
+	before := archive.MonthOf(time.Now().Add(-p.ConfigFile.Archive.After))
+
+	counts, err := p.Repository.ArchiveHistory(before)
+	if err != nil {
+		logger.Warn("Failed to archive history, if you see this multiple days in a row, there's something wrong", zap.Error(err))
+	}
+
+	var archived []string
+	for table, count := range counts {
+		if count > 0 {
+			archived = append(archived, fmt.Sprintf("%d %s", count, table))
+		}
+	}
+	if len(archived) == 0 {
+		logger.Debug("Nothing to archive before " + before.Format(time.DateOnly))
+		return
+	}
+	sort.Strings(archived)
+	p.Logger.Info(fmt.Sprintf("🗄 Archived history before %s: %s", before.Format(time.DateOnly), strings.Join(archived, ", ")))
+}
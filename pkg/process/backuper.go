@@ -0,0 +1,134 @@
+package process
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/prprprus/scheduler"
+	"go.uber.org/zap"
+
+	"github.com/erwanlbp/trading-bot/pkg/config/configfile"
+	"github.com/erwanlbp/trading-bot/pkg/db"
+	"github.com/erwanlbp/trading-bot/pkg/db/postgres"
+	"github.com/erwanlbp/trading-bot/pkg/log"
+	"github.com/erwanlbp/trading-bot/pkg/util"
+)
+
+// Daily snapshot of the DB, kept as a weekly one too when the last weekly is a week old
+type Backuper struct {
+	Logger     *log.Logger
+	DB         *db.DB
+	ConfigFile *configfile.ConfigFile
+}
+
+func NewBackuper(l *log.Logger, d *db.DB, cf *configfile.ConfigFile) *Backuper {
+	return &Backuper{
+		Logger:     l,
+		DB:         d,
+		ConfigFile: cf,
+	}
+}
+
+func (p *Backuper) Start(ctx context.Context) {
+	if !p.ConfigFile.Backup.Enabled {
+		return
+	}
+
+	go func() {
+
+		Scheduler, _ := scheduler.NewScheduler(1000)
+
+		id := Scheduler.Every().Hour(3).Minute(30).Second(0).Do(p.Backup, ctx) // 5:30AM Europe/Paris
+
+		// If ctx is canceled, we'll stop the job
+		<-ctx.Done()
+
+		if err := Scheduler.CancelJob(id); err != nil {
+			p.Logger.Error("failed canceling job", zap.Error(err))
+		}
+	}()
+}
+
+func (p *Backuper) Backup(ctx context.Context) {
+	logger := p.Logger.With(zap.String("process", "backuper"))
+
+	conf := p.ConfigFile.Backup
+	dir := conf.Path()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		logger.Error("Failed to create backup dir", zap.Error(err))
+		return
+	}
+
+	backups, err := db.ListBackups(dir)
+	if err != nil {
+		logger.Error("Failed to list backups", zap.Error(err))
+		return
+	}
+
+	now := time.Now().UTC()
+	path, err := p.snapshot(ctx, dir, now)
+	if err != nil {
+		logger.Error("Failed to backup the DB", zap.Error(err))
+		return
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		logger.Error("Failed to read backup size", zap.Error(err))
+		return
+	}
+
+	weekly := true
+	for _, b := range backups {
+		// Margin for the scheduler firing a bit early
+		if b.Kind == db.BackupWeekly && now.Sub(b.Time) < 7*24*time.Hour-time.Hour {
+			weekly = false
+		}
+	}
+	if weekly {
+		_, ext, _ := strings.Cut(filepath.Base(path), ".")
+		weeklyPath := filepath.Join(dir, db.BackupFileName(db.BackupWeekly, now, ext))
+		if err := db.CopyFile(path, weeklyPath); err != nil {
+			logger.Error("Failed to copy the weekly backup", zap.Error(err))
+		}
+	}
+
+	removed, err := db.RotateBackups(dir, conf.Daily, conf.Weekly)
+	if err != nil {
+		logger.Error("Failed to remove old backups", zap.Error(err))
+	}
+
+	msg := fmt.Sprintf("💾 DB backed up, %s", util.ByteCountSI(info.Size()))
+	if weekly {
+		msg += ", kept as weekly backup too"
+	}
+	if len(removed) > 0 {
+		msg += fmt.Sprintf(", %d old backup(s) removed", len(removed))
+	}
+	p.Logger.Info(msg)
+}
+
+// Write the daily backup, return its path
+func (p *Backuper) snapshot(ctx context.Context, dir string, now time.Time) (string, error) {
+	if p.DB.Dialect() == db.DialectPostgres {
+		path := filepath.Join(dir, db.BackupFileName(db.BackupDaily, now, "dump"))
+		return path, postgres.DumpToFile(ctx, p.ConfigFile.Database.DSN, path)
+	}
+
+	path := filepath.Join(dir, db.BackupFileName(db.BackupDaily, now, "db"))
+	if err := p.DB.VacuumInto(path); err != nil {
+		return "", err
+	}
+	if !p.ConfigFile.Backup.Gzip {
+		return path, nil
+	}
+
+	defer os.Remove(path)
+	if err := db.GzipFile(path, path+".gz"); err != nil {
+		return "", fmt.Errorf("failed to gzip: %w", err)
+	}
+	return path + ".gz", nil
+}
@@ -5,6 +5,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/shopspring/decimal"
 	"go.uber.org/zap"
 
 	"github.com/erwanlbp/trading-bot/pkg/binance"
@@ -34,7 +35,7 @@ func NewBalanceSaver(l *log.Logger, r *repository.Repository, e *eventbus.Bus, b
 }
 
 func (p *BalanceSaver) Start(ctx context.Context) {
-	sub := p.EventBus.Subscribe(eventbus.SaveBalance)
+	sub := eventbus.SubscribeWith(p.EventBus, eventbus.LatestOnly, eventbus.SaveBalance)
 	go sub.Handler(ctx, p.SaveBalanceBus)
 
 	go func() {
@@ -58,11 +59,31 @@ func (p *BalanceSaver) Start(ctx context.Context) {
 	}()
 }
 
+// Coins the balance is valued in: USDT, BTC and the bridge
+func BalanceAltCoins(bridge string) []string {
+	altCoins := []string{constant.USDT, constant.BTC}
+	if bridge != constant.USDT && bridge != constant.BTC {
+		altCoins = append(altCoins, bridge)
+	}
+	return altCoins
+}
+
+// Balance history point from the values of GetBalanceValue in BalanceAltCoins
+func NewBalanceHistory(value map[string]decimal.Decimal, bridge string, ts time.Time) model.BalanceHistory {
+	return model.BalanceHistory{
+		BtcBalance:    value[constant.BTC],
+		UsdtBalance:   value[constant.USDT],
+		Bridge:        bridge,
+		BridgeBalance: value[bridge],
+		Timestamp:     ts,
+	}
+}
+
 func (p *BalanceSaver) SaveBalanceBatch(ctx context.Context) {
 	p.SaveBalance(ctx)
 }
 
-func (p *BalanceSaver) SaveBalanceBus(ctx context.Context, _ eventbus.Event) {
+func (p *BalanceSaver) SaveBalanceBus(ctx context.Context, _ eventbus.NoPayload) {
 	p.SaveBalance(ctx)
 }
 
@@ -82,17 +103,15 @@ func (p *BalanceSaver) SaveBalance(ctx context.Context) {
 	// return
 	// }
 
-	value, err := p.BinanceClient.GetBalanceValue(ctx, []string{constant.USDT, constant.BTC})
+	bridge := p.BinanceClient.ConfigFile.Bridge
+
+	value, err := p.BinanceClient.GetBalanceValue(ctx, BalanceAltCoins(bridge))
 	if err != nil {
 		p.Logger.Error("failed getting balance value", zap.Error(err))
 		return
 	}
 
-	balanceToSave := model.BalanceHistory{
-		BtcBalance:  value[constant.BTC],
-		UsdtBalance: value[constant.USDT],
-		Timestamp:   time.Now().UTC(),
-	}
+	balanceToSave := NewBalanceHistory(value, bridge, time.Now().UTC())
 
 	if err := repository.SimpleUpsert(p.Repository.DB.DB, balanceToSave); err != nil {
 		p.Logger.Error("failed saving balance", zap.Error(err))
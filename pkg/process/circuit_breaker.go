@@ -0,0 +1,238 @@
+package process
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prprprus/scheduler"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+
+	"github.com/erwanlbp/trading-bot/pkg/binance"
+	"github.com/erwanlbp/trading-bot/pkg/config/configfile"
+	"github.com/erwanlbp/trading-bot/pkg/eventbus"
+	"github.com/erwanlbp/trading-bot/pkg/log"
+	"github.com/erwanlbp/trading-bot/pkg/repository"
+	"github.com/erwanlbp/trading-bot/pkg/util"
+)
+
+// Halts the jump finder when the portfolio loses too much or jumps keep failing, until a manual resume
+type CircuitBreaker struct {
+	Logger     *log.Logger
+	Binance    *binance.Client
+	Repository *repository.Repository
+	EventBus   *eventbus.Bus
+	ConfigFile *configfile.ConfigFile
+}
+
+func NewCircuitBreaker(l *log.Logger,
+	r *repository.Repository,
+	eb *eventbus.Bus,
+	cf *configfile.ConfigFile,
+	bc *binance.Client) *CircuitBreaker {
+	return &CircuitBreaker{
+		Logger:     l,
+		Repository: r,
+		EventBus:   eb,
+		ConfigFile: cf,
+		Binance:    bc,
+	}
+}
+
+func (p *CircuitBreaker) Start(ctx context.Context) {
+	go func() {
+
+		Scheduler, _ := scheduler.NewScheduler(1000)
+
+		// Every minute, between two price fetches
+		id := Scheduler.Every().Second(30).Do(p.CheckLimits, ctx)
+
+		// If ctx is canceled, we'll stop the job
+		<-ctx.Done()
+
+		if err := Scheduler.CancelJob(id); err != nil {
+			p.Logger.Error("failed canceling job", zap.Error(err))
+		}
+	}()
+}
+
+func (p *CircuitBreaker) IsTripped() bool {
+	if !p.ConfigFile.CircuitBreaker.Enabled {
+		return false
+	}
+	cb, err := p.Repository.GetCircuitBreaker()
+	if err != nil {
+		// Better safe than sorry
+		p.Logger.Error("Failed to get circuit breaker state, considering it tripped", zap.Error(err))
+		return true
+	}
+	return cb.Tripped
+}
+
+func (p *CircuitBreaker) CheckLimits(ctx context.Context) {
+	logger := p.Logger.With(zap.String("process", "circuit_breaker"))
+
+	conf := p.ConfigFile.CircuitBreaker
+	if !conf.Enabled || (conf.MaxDailyLoss.IsZero() && conf.MaxDrawdown.IsZero()) {
+		return
+	}
+
+	cb, err := p.Repository.GetCircuitBreaker()
+	if err != nil {
+		logger.Error("Failed to get circuit breaker state", zap.Error(err))
+		return
+	}
+	if cb.Tripped {
+		return
+	}
+
+	bridge := p.ConfigFile.Bridge
+	values, err := p.Binance.GetBalanceValue(ctx, BalanceAltCoins(bridge))
+	if err != nil {
+		logger.Error("Failed to get balance value", zap.Error(err))
+		return
+	}
+	value := values[bridge]
+
+	// Balance history is otherwise only saved on jumps and daily, the limits would be measured against stale points
+	if err := p.SaveBalancePoint(values, cb.ResumedOn, time.Now().UTC()); err != nil {
+		logger.Warn("Failed to save balance point", zap.Error(err))
+	}
+
+	if !conf.MaxDailyLoss.IsZero() {
+		dayStart, err := p.DayStartValue(cb.ResumedOn)
+		if err != nil {
+			logger.Error("Failed to get balance at the start of the day", zap.Error(err))
+		} else if loss := lossPct(dayStart, value); loss.GreaterThanOrEqual(conf.MaxDailyLoss) {
+			p.Trip(ctx, fmt.Sprintf("daily loss of %s %% (%s → %s %s) reached the %s %% limit", loss.StringFixed(2), dayStart.StringFixed(2), value.StringFixed(2), bridge, conf.MaxDailyLoss))
+			return
+		}
+	}
+
+	if !conf.MaxDrawdown.IsZero() {
+		peak, err := p.Repository.GetPeakBalanceSince(cb.ResumedOn, bridge)
+		if err != nil {
+			logger.Error("Failed to get peak balance", zap.Error(err))
+		} else if drawdown := lossPct(decimal.Max(peak, value), value); drawdown.GreaterThanOrEqual(conf.MaxDrawdown) {
+			p.Trip(ctx, fmt.Sprintf("drawdown of %s %% (%s → %s %s) reached the %s %% limit", drawdown.StringFixed(2), peak.StringFixed(2), value.StringFixed(2), bridge, conf.MaxDrawdown))
+			return
+		}
+	}
+}
+
+// Minimum time between two balance points saved by the circuit breaker, unless the value is a new peak
+const balancePointInterval = 15 * time.Minute
+
+// Save the evaluated balance when it's a new peak since resumed, so the drawdown is measured from it, or when the last point is too old
+func (p *CircuitBreaker) SaveBalancePoint(values map[string]decimal.Decimal, resumedOn, now time.Time) error {
+	bridge := p.ConfigFile.Bridge
+
+	last, err := p.Repository.GetLastBalanceHistory()
+	if err != nil {
+		return fmt.Errorf("failed to get last balance: %w", err)
+	}
+	peak, err := p.Repository.GetPeakBalanceSince(resumedOn, bridge)
+	if err != nil {
+		return fmt.Errorf("failed to get peak balance: %w", err)
+	}
+	if now.Sub(last.Timestamp) < balancePointInterval && !values[bridge].GreaterThan(peak) {
+		return nil
+	}
+
+	return repository.SimpleUpsert(p.Repository.DB.DB, NewBalanceHistory(values, bridge, now))
+}
+
+// Value of the portfolio in the bridge at the start of the day, or when resumed if it's later
+func (p *CircuitBreaker) DayStartValue(resumedOn time.Time) (decimal.Decimal, error) {
+	dayStart := time.Now().UTC().Truncate(util.Day)
+
+	if resumedOn.Before(dayStart) {
+		history, err := p.Repository.GetLastBalanceHistoryBefore(dayStart)
+		if err != nil {
+			return decimal.Zero, err
+		}
+		if !history.Timestamp.IsZero() && !history.Timestamp.Before(resumedOn) {
+			return history.ValueIn(p.ConfigFile.Bridge), nil
+		}
+	}
+
+	history, err := p.Repository.GetFirstBalanceHistoryAfter(util.MaxTime(dayStart, resumedOn))
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return history.ValueIn(p.ConfigFile.Bridge), nil
+}
+
+// Count the failed jumps in a row, trip if there are too many
+func (p *CircuitBreaker) RecordJump(ctx context.Context, jumpErr error) {
+	if jumpErr == nil {
+		if err := p.Repository.ResetFailedJumps(); err != nil {
+			p.Logger.Error("Failed to reset failed jumps count", zap.Error(err))
+		}
+		return
+	}
+
+	count, err := p.Repository.AddFailedJump()
+	if err != nil {
+		p.Logger.Error("Failed to count failed jump", zap.Error(err))
+		return
+	}
+
+	conf := p.ConfigFile.CircuitBreaker
+	if conf.Enabled && conf.MaxFailedJumps > 0 && count >= conf.MaxFailedJumps {
+		p.Trip(ctx, fmt.Sprintf("%d jumps failed in a row", count))
+	}
+}
+
+func (p *CircuitBreaker) Trip(ctx context.Context, reason string) {
+	if err := p.Repository.TripCircuitBreaker(reason, time.Now().UTC()); err != nil {
+		p.Logger.Error("Failed to save circuit breaker state", zap.Error(err))
+	}
+
+	p.Logger.Error(fmt.Sprintf("🚨 Circuit breaker tripped: %s. Jumps are halted until /resume", reason))
+
+	if !p.ConfigFile.CircuitBreaker.ExitToBridge {
+		return
+	}
+	if err := p.ExitToBridge(ctx); err != nil {
+		p.Logger.Error("Failed to exit to the bridge", zap.Error(err))
+		return
+	}
+	eventbus.Publish(p.EventBus, eventbus.SaveBalance, eventbus.NoPayload{})
+}
+
+func (p *CircuitBreaker) ExitToBridge(ctx context.Context) error {
+	release, err := p.Binance.TradeLock()
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	currentCoin, hasEverJumped, err := p.Repository.GetCurrentCoin()
+	if err != nil {
+		return fmt.Errorf("failed getting current coin: %w", err)
+	}
+	if !hasEverJumped || currentCoin.Coin == p.ConfigFile.Bridge {
+		return nil
+	}
+
+	sell, err := p.Binance.Sell(ctx, currentCoin.Coin, p.ConfigFile.Bridge)
+	if err != nil {
+		return fmt.Errorf("failed to sell %s: %w", util.LogSymbol(currentCoin.Coin, p.ConfigFile.Bridge), err)
+	}
+	if _, err := p.Repository.SetCurrentCoin(p.ConfigFile.Bridge, sell.Time()); err != nil {
+		return fmt.Errorf("failed to set current coin to bridge: %w", err)
+	}
+
+	p.Logger.Info(fmt.Sprintf("Exited %s to %s", currentCoin.Coin, p.ConfigFile.Bridge))
+
+	return nil
+}
+
+func lossPct(from, to decimal.Decimal) decimal.Decimal {
+	if from.IsZero() {
+		return decimal.Zero
+	}
+	return decimal.NewFromInt(1).Sub(to.Div(from)).Mul(decimal.NewFromInt(100))
+}
@@ -3,10 +3,12 @@ package process
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/prprprus/scheduler"
 	"go.uber.org/zap"
 
+	"github.com/erwanlbp/trading-bot/pkg/config/configfile"
 	"github.com/erwanlbp/trading-bot/pkg/config/globalconf"
 	"github.com/erwanlbp/trading-bot/pkg/log"
 	"github.com/erwanlbp/trading-bot/pkg/repository"
@@ -16,13 +18,15 @@ import (
 type Cleaner struct {
 	Logger     *log.Logger
 	Repository *repository.Repository
+	ConfigFile *configfile.ConfigFile
 	GlobalConf globalconf.GlobalConfModifier
 }
 
-func NewCleaner(l *log.Logger, r *repository.Repository, gc globalconf.GlobalConfModifier) *Cleaner {
+func NewCleaner(l *log.Logger, r *repository.Repository, cf *configfile.ConfigFile, gc globalconf.GlobalConfModifier) *Cleaner {
 	return &Cleaner{
 		Logger:     l,
 		Repository: r,
+		ConfigFile: cf,
 		GlobalConf: gc,
 	}
 }
@@ -35,9 +39,8 @@ func (p *Cleaner) Start(ctx context.Context) {
 		var ids []string
 
 		ids = append(ids,
-			Scheduler.Every().Hour(6).Minute(15).Second(15).Do(p.CleanPairHistory), // 8AM Europe/Paris
-			Scheduler.Every().Hour(6).Minute(0).Second(15).Do(p.CleanCoinPrice),    // 8AM Europe/Paris
-			Scheduler.Every().Hour(8).Minute(0).Second(15).Do(p.VacuumDB),          // 10AM Europe/Paris
+			Scheduler.Every().Hour(6).Minute(0).Second(15).Do(p.ApplyRetention), // 8AM Europe/Paris
+			Scheduler.Every().Hour(8).Minute(0).Second(15).Do(p.VacuumDB),       // 10AM Europe/Paris
 		)
 
 		// If ctx is canceled, we'll stop the job
@@ -51,19 +54,26 @@ func (p *Cleaner) Start(ctx context.Context) {
 	}()
 }
 
-func (p *Cleaner) CleanPairHistory() {
-	if inserted, deleted, err := p.Repository.CleanOldPairHistory(); err != nil {
-		p.Logger.Warn("Failed to clean pair history, if you this multiple days in a row, there's something wrong", zap.Error(err))
-	} else {
-		p.Logger.Debug(fmt.Sprintf("Inserted %d aggregated pair history, deleted %d lines after aggregation", inserted, deleted), zap.String("process", "cleaner"))
-	}
-}
+// Downsample the history, tier by tier from the finest
+func (p *Cleaner) ApplyRetention() {
+	logger := p.Logger.With(zap.String("process", "cleaner"))
 
-func (p *Cleaner) CleanCoinPrice() {
-	if inserted, deleted, err := p.Repository.CleanCoinPriceHistory(); err != nil {
-		p.Logger.Warn("Failed to clean coin price history, if you this multiple days in a row, there's something wrong", zap.Error(err))
-	} else {
-		p.Logger.Debug(fmt.Sprintf("Inserted %d aggregated coin price, deleted %d lines after aggregation", inserted, deleted), zap.String("process", "cleaner"))
+	now := time.Now().UTC()
+	for _, tier := range p.ConfigFile.Retention.Tiers {
+		// Only whole buckets
+		before := now.Add(-tier.After).Truncate(tier.Resolution)
+
+		if inserted, deleted, err := p.Repository.DownsamplePairHistory(tier.Resolution, before); err != nil {
+			logger.Warn("Failed to downsample pair history, if you see this multiple days in a row, there's something wrong", zap.Error(err))
+		} else {
+			logger.Debug(fmt.Sprintf("Downsampled pair history older than %s to %s: inserted %d buckets, deleted %d lines", tier.After, tier.Resolution, inserted, deleted))
+		}
+
+		if inserted, deleted, err := p.Repository.DownsampleCoinPrices(tier.Resolution, before); err != nil {
+			logger.Warn("Failed to downsample coin price history, if you see this multiple days in a row, there's something wrong", zap.Error(err))
+		} else {
+			logger.Debug(fmt.Sprintf("Downsampled coin price history older than %s to %s: inserted %d buckets, deleted %d lines", tier.After, tier.Resolution, inserted, deleted))
+		}
 	}
 }
 
@@ -78,6 +88,6 @@ func (p *Cleaner) VacuumDB() {
 		} else {
 			sizeStr = util.ByteCountSI(size)
 		}
-		p.Logger.Info(fmt.Sprintf("Vacuumed, DB file size now is %s", sizeStr), zap.String("process", "cleaner"))
+		p.Logger.Info(fmt.Sprintf("Vacuumed, DB size now is %s", sizeStr), zap.String("process", "cleaner"))
 	}
 }
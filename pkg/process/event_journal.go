@@ -0,0 +1,55 @@
+package process
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/erwanlbp/trading-bot/pkg/config/configfile"
+	"github.com/erwanlbp/trading-bot/pkg/eventbus"
+	"github.com/erwanlbp/trading-bot/pkg/log"
+)
+
+// Record every event of the bus in the journal files
+type EventJournal struct {
+	Logger     *log.Logger
+	EventBus   *eventbus.Bus
+	ConfigFile *configfile.ConfigFile
+}
+
+func NewEventJournal(l *log.Logger, eb *eventbus.Bus, cf *configfile.ConfigFile) *EventJournal {
+	return &EventJournal{
+		Logger:     l,
+		EventBus:   eb,
+		ConfigFile: cf,
+	}
+}
+
+func (p *EventJournal) Start(ctx context.Context) {
+	conf := p.ConfigFile.Journal
+	if !conf.Enabled {
+		return
+	}
+
+	logger := p.Logger.With(zap.String("process", "event_journal"))
+
+	writer, err := eventbus.NewJournalWriter(conf.Path(), conf.MaxSize*1024*1024, conf.MaxFiles)
+	if err != nil {
+		logger.Error("Failed to open the event journal, events won't be recorded", zap.Error(err))
+		return
+	}
+
+	// A slow disk must not slow down the trading, events are dropped instead
+	sub := p.EventBus.SubscribeAll(eventbus.SubscriptionOptions{BufferSize: 1024, Policy: eventbus.DropNewest})
+
+	go func() {
+		defer writer.Close()
+
+		sub.Handler(ctx, func(_ context.Context, event eventbus.Event) {
+			if err := writer.Write(time.Now(), event); err != nil {
+				logger.Error("Failed to record event "+event.Name, zap.Error(err))
+			}
+		})
+	}()
+}
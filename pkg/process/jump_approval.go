@@ -0,0 +1,250 @@
+package process
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+	"gopkg.in/telebot.v3"
+
+	"github.com/erwanlbp/trading-bot/pkg/binance"
+	"github.com/erwanlbp/trading-bot/pkg/eventbus"
+	"github.com/erwanlbp/trading-bot/pkg/model"
+)
+
+const (
+	approveJumpUnique = "approve_jump"
+	rejectJumpUnique  = "reject_jump"
+)
+
+// Handle the Approve/Reject buttons of the jump proposals, must be called before starting the bot
+func (p *JumpFinder) InitTelegramHandlers(ctx context.Context) {
+	if p.TelegramClient == nil {
+		return
+	}
+
+	p.TelegramClient.CreateHandler(&telebot.Btn{Unique: approveJumpUnique}, func(c telebot.Context) error {
+		return p.answerProposal(c, "⏳ Executing…", func(id uint) string { return p.ApproveProposal(ctx, id) })
+	})
+	p.TelegramClient.CreateHandler(&telebot.Btn{Unique: rejectJumpUnique}, func(c telebot.Context) error {
+		return p.answerProposal(c, "Rejecting…", p.RejectProposal)
+	})
+}
+
+// The callback is answered right away, telegram expires it while the trade runs, the message is edited with the result once done
+func (p *JumpFinder) answerProposal(c telebot.Context, progress string, decide func(id uint) string) error {
+	id, err := strconv.ParseUint(c.Data(), 10, 64)
+	if err != nil {
+		return c.Respond(&telebot.CallbackResponse{Text: "Invalid proposal " + c.Data()})
+	}
+
+	if err := c.Respond(&telebot.CallbackResponse{Text: progress}); err != nil {
+		p.Logger.Warn("Failed to answer jump proposal callback", zap.Error(err))
+	}
+
+	// Editing without the buttons removes them, so they can't be clicked again meanwhile
+	text := c.Message().Text
+	if err := c.Edit(text + "\n\n" + progress); err != nil {
+		p.Logger.Warn("Failed to edit jump proposal message", zap.Error(err))
+	}
+
+	result := decide(uint(id))
+
+	return c.Edit(text + "\n\n" + result)
+}
+
+// Save the proposal and send it on telegram with Approve/Reject buttons
+func (p *JumpFinder) ProposeJump(path JumpPath, wantedGain, quantity decimal.Decimal, slippages []binance.SlippageEstimate) error {
+	if p.TelegramClient == nil {
+		return fmt.Errorf("no telegram client to send the proposal")
+	}
+
+	expected := quantity
+	for i, hop := range path.Hops {
+		if slippages[i].BuyVWAP.IsZero() {
+			return fmt.Errorf("no buy price for %s", hop.Pair.Pair.LogSymbol())
+		}
+		expected = expected.Mul(slippages[i].SellVWAP).Div(slippages[i].BuyVWAP).Mul(hop.Fee)
+	}
+
+	coins := path.Coins()
+	proposal := model.JumpProposal{
+		CreatedOn:          time.Now().UTC(),
+		Path:               strings.Join(coins, ","),
+		Diff:               path.Diff,
+		NeededDiff:         wantedGain,
+		Fee:                path.Fee(),
+		FromQuantity:       quantity,
+		ExpectedToQuantity: expected,
+		Status:             model.JumpProposalPending,
+	}
+	if err := p.Repository.CreateJumpProposal(&proposal); err != nil {
+		return fmt.Errorf("failed to save proposal: %w", err)
+	}
+
+	id := strconv.FormatUint(uint64(proposal.ID), 10)
+	markup := &telebot.ReplyMarkup{}
+	markup.Inline(markup.Row(
+		markup.Data("✅ Approve", approveJumpUnique, id),
+		markup.Data("❌ Reject", rejectJumpUnique, id),
+	))
+
+	p.TelegramClient.SendWithOptions(strings.Join([]string{
+		fmt.Sprintf("🤔 Jump proposal #%s: %s", id, path.LogSymbol()),
+		fmt.Sprintf("Diff: %s (needed %s)", path.Diff.StringFixed(5), wantedGain.StringFixed(5)),
+		fmt.Sprintf("Fees: %s %%", decimal.NewFromInt(1).Sub(proposal.Fee).Mul(decimal.NewFromInt(100)).StringFixed(3)),
+		fmt.Sprintf("Sell: %s %s", quantity, coins[0]),
+		fmt.Sprintf("Expected: ~%s %s", expected.StringFixed(6), coins[len(coins)-1]),
+		fmt.Sprintf("Approve within %s", p.ConfigFile.Approval.Window),
+	}, "\n"), markup)
+
+	p.Logger.Debug(fmt.Sprintf("Proposed jump %s", path.LogSymbol()), zap.Uint("proposal", proposal.ID))
+
+	return nil
+}
+
+func (p *JumpFinder) ExpireProposals(logger *zap.Logger) {
+	now := time.Now().UTC()
+	count, err := p.Repository.ExpireJumpProposals(now.Add(-p.ConfigFile.Approval.Window), now)
+	if err != nil {
+		logger.Error("Failed to expire jump proposals", zap.Error(err))
+		return
+	}
+	if count > 0 {
+		logger.Info(fmt.Sprintf("%d jump proposal(s) expired without approval", count))
+	}
+}
+
+func (p *JumpFinder) RejectProposal(id uint) string {
+	proposal, err := p.pendingProposal(id)
+	if err != nil {
+		return err.Error()
+	}
+
+	proposal.Status = model.JumpProposalRejected
+	proposal.DecidedOn = time.Now().UTC()
+	if err := p.Repository.SaveJumpProposal(proposal); err != nil {
+		return "Failed to save rejection: " + err.Error()
+	}
+
+	return "❌ Rejected"
+}
+
+// Re-check the proposal diff with the last prices and jump if it still holds, return the outcome
+func (p *JumpFinder) ApproveProposal(ctx context.Context, id uint) string {
+	proposal, err := p.pendingProposal(id)
+	if err != nil {
+		return err.Error()
+	}
+
+	proposal.DecidedOn = time.Now().UTC()
+	save := func(status model.JumpProposalStatus, result string) string {
+		proposal.Status = status
+		if err := p.Repository.SaveJumpProposal(proposal); err != nil {
+			p.Logger.Error("Failed to save jump proposal", zap.Uint("proposal", proposal.ID), zap.Error(err))
+		}
+		return result
+	}
+
+	if proposal.DecidedOn.After(proposal.CreatedOn.Add(p.ConfigFile.Approval.Window)) {
+		return save(model.JumpProposalExpired, "⌛ Expired, approved too late")
+	}
+	if p.CircuitBreaker.IsTripped() {
+		return save(model.JumpProposalOutdated, "🚨 Circuit breaker is tripped, not jumping")
+	}
+	if paused, err := p.Repository.IsPaused(); err != nil || paused {
+		return save(model.JumpProposalOutdated, "⏸️ Jumps are paused, not jumping")
+	}
+	if !p.IsTradingAllowed(p.Logger.Logger, proposal.DecidedOn) {
+		return save(model.JumpProposalOutdated, "🚫 Outside of trading windows, not jumping")
+	}
+
+	path, slippages, wantedGain, err := p.RecheckProposal(ctx, proposal)
+	if err != nil {
+		return save(model.JumpProposalOutdated, "Not jumping anymore: "+err.Error())
+	}
+	proposal.RecheckDiff = path.Diff
+	if path.Diff.LessThan(wantedGain) {
+		return save(model.JumpProposalOutdated, fmt.Sprintf("Diff doesn't hold anymore (%s < %s), not jumping", path.Diff.StringFixed(5), wantedGain.StringFixed(5)))
+	}
+
+	eventbus.Publish(p.EventBus, JumpDecided, JumpDecision{
+		Timestamp:    proposal.DecidedOn,
+		Path:         path.Coins(),
+		Diff:         path.Diff,
+		WantedGain:   wantedGain,
+		FromQuantity: proposal.FromQuantity,
+		ProposalID:   proposal.ID,
+	})
+
+	if err := p.ExecutePath(ctx, path, slippages); err != nil {
+		p.Logger.Error("Failed to jump", zap.Error(err))
+		eventbus.Publish(p.EventBus, eventbus.SaveBalance, eventbus.NoPayload{})
+		return save(model.JumpProposalFailed, "Jump failed: "+err.Error())
+	}
+
+	eventbus.Publish(p.EventBus, eventbus.SaveBalance, eventbus.NoPayload{})
+	return save(model.JumpProposalExecuted, "✅ Jumped "+proposal.LogSymbol())
+}
+
+func (p *JumpFinder) pendingProposal(id uint) (model.JumpProposal, error) {
+	proposal, exists, err := p.Repository.GetJumpProposal(id)
+	if err != nil {
+		return proposal, fmt.Errorf("failed to get proposal: %w", err)
+	}
+	if !exists {
+		return proposal, fmt.Errorf("unknown proposal #%d", id)
+	}
+	if proposal.Status != model.JumpProposalPending {
+		return proposal, fmt.Errorf("proposal is already %s", proposal.Status)
+	}
+	return proposal, nil
+}
+
+// Compute again the path of the proposal with the last prices, the returned diff has the expected slippage removed
+func (p *JumpFinder) RecheckProposal(ctx context.Context, proposal model.JumpProposal) (JumpPath, []binance.SlippageEstimate, decimal.Decimal, error) {
+	logger := p.Logger.With(zap.String("process", "jump_finder"), zap.Uint("proposal", proposal.ID))
+
+	coins := proposal.Coins()
+
+	currentCoin, _, err := p.Repository.GetCurrentCoin()
+	if err != nil {
+		return JumpPath{}, nil, decimal.Zero, fmt.Errorf("failed getting current coin: %w", err)
+	}
+	if currentCoin.Coin != coins[0] {
+		return JumpPath{}, nil, decimal.Zero, fmt.Errorf("current coin is now %s", currentCoin.Coin)
+	}
+
+	p.mtx.Lock()
+	lastCheck := p.lastCheck
+	p.mtx.Unlock()
+
+	pairsRatio, err := p.CalculateRatios(ctx, lastCheck)
+	if err != nil {
+		return JumpPath{}, nil, decimal.Zero, fmt.Errorf("failed to calculate ratios: %w", err)
+	}
+
+	wantedGain := decimal.NewFromInt(1).Add(p.ConfigFile.Jump.GetNeededGain(currentCoin.Timestamp))
+
+	jumpsFrom, _ := p.JumpCandidates(ctx, logger, pairsRatio, "", wantedGain)
+	path, ok := PathThrough(coins, jumpsFrom)
+	if !ok {
+		return JumpPath{}, nil, decimal.Zero, fmt.Errorf("no valid price for one of the coins")
+	}
+
+	balances, err := p.Binance.GetBalance(ctx, currentCoin.Coin)
+	if err != nil {
+		return JumpPath{}, nil, decimal.Zero, fmt.Errorf("failed to get current coin balance: %w", err)
+	}
+	slippages, slippageMultiplier, err := p.EstimatePathSlippage(ctx, path, balances[currentCoin.Coin])
+	if err != nil {
+		return JumpPath{}, nil, decimal.Zero, fmt.Errorf("failed to estimate slippage: %w", err)
+	}
+	path.Diff = path.Diff.Mul(slippageMultiplier)
+
+	return path, slippages, wantedGain, nil
+}
@@ -0,0 +1,55 @@
+package process
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/erwanlbp/trading-bot/pkg/eventbus"
+	"github.com/erwanlbp/trading-bot/pkg/model"
+)
+
+// Diffs computed on a tick, whether a jump follows or not
+type JumpEvaluation struct {
+	Timestamp   time.Time
+	CurrentCoin string
+	WantedGain  decimal.Decimal
+	Diffs       []model.Diff
+	// False outside of trading windows or during a blackout
+	TradingAllowed bool
+}
+
+// Path chosen to jump, once the expected slippage is removed
+type JumpDecision struct {
+	Timestamp  time.Time
+	Path       []string
+	Diff       decimal.Decimal
+	WantedGain decimal.Decimal
+	// Quantity of the current coin to sell
+	FromQuantity decimal.Decimal
+	// Ratio (between 0 and 1) of value expected to be lost walking the order books
+	ExpectedSlippage decimal.Decimal
+	// Proposed on telegram, the jump is done only once approved
+	PendingApproval bool
+	// Set when the jump is done because a proposal got approved
+	ProposalID uint
+}
+
+type JumpResult struct {
+	Jump model.Jump
+}
+
+type JumpFailure struct {
+	Timestamp time.Time
+	FromCoin  string
+	ToCoin    string
+	Manual    bool
+	Reason    string
+}
+
+var (
+	JumpEvaluated = eventbus.NewTopic[JumpEvaluation]("jump_evaluated")
+	JumpDecided   = eventbus.NewTopic[JumpDecision]("jump_decided")
+	JumpCompleted = eventbus.NewTopic[JumpResult]("jump_completed")
+	JumpFailed    = eventbus.NewTopic[JumpFailure]("jump_failed")
+)
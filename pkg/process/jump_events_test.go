@@ -0,0 +1,132 @@
+package process_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/erwanlbp/trading-bot/pkg/binance"
+	"github.com/erwanlbp/trading-bot/pkg/binance/binancetest"
+	"github.com/erwanlbp/trading-bot/pkg/config/configfile"
+	"github.com/erwanlbp/trading-bot/pkg/db"
+	"github.com/erwanlbp/trading-bot/pkg/db/sqlite"
+	"github.com/erwanlbp/trading-bot/pkg/eventbus"
+	"github.com/erwanlbp/trading-bot/pkg/log"
+	"github.com/erwanlbp/trading-bot/pkg/model"
+	"github.com/erwanlbp/trading-bot/pkg/process"
+	"github.com/erwanlbp/trading-bot/pkg/repository"
+)
+
+type noBlacklist struct{}
+
+func (noBlacklist) IsSymbolBlacklisted(string) bool { return false }
+
+// Jump finder on ETH, trading against the stub server
+func newStubJumpFinder(t *testing.T) (*process.JumpFinder, *binancetest.Server) {
+	logger := log.NewSimpleZapLogger()
+	cf := &configfile.ConfigFile{Bridge: "USDT", Coins: []string{"ETH", "BTC"}, TradeTimeout: time.Second}
+	cf.Order.Refresh = 10 * time.Millisecond
+
+	gormDB, err := sqlite.NewDB(logger, filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	database := db.NewDB(gormDB)
+	require.NoError(t, database.MigrateSchema())
+	repo := repository.NewRepository(database, cf, logger)
+
+	require.NoError(t, repository.SimpleUpsert(gormDB, model.Coin{Coin: "ETH", Enabled: true}, model.Coin{Coin: "BTC", Enabled: true}))
+	require.NoError(t, repository.SimpleUpsert(gormDB,
+		model.Pair{FromCoin: "ETH", ToCoin: "BTC", Exists: true},
+		model.Pair{FromCoin: "BTC", ToCoin: "ETH", Exists: true},
+	))
+	_, err = repo.SetCurrentCoin("ETH", time.Now().UTC())
+	require.NoError(t, err)
+
+	server := binancetest.NewServer(t)
+	server.Balances = map[string]string{"ETH": "2", "BTC": "0", "USDT": "6000"}
+	server.Prices = map[string]string{"ETHUSDT": "3000", "BTCUSDT": "60000"}
+
+	bus := eventbus.NewEventBus()
+	client := binance.NewClient(logger, cf, bus, noBlacklist{}, repo)
+	client.SetAPIEndpoint(server.URL)
+
+	cb := process.NewCircuitBreaker(logger, repo, bus, cf, client)
+	return process.NewJumpFinder(logger, repo, bus, cf, client, cb, nil), server
+}
+
+// First event published on the topic, the jump is synchronous so it's already queued
+func firstEvent[T any](t *testing.T, sub eventbus.TypedSubscription[T]) T {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	var res T
+	received := false
+	sub.Handler(ctx, func(_ context.Context, payload T) {
+		res, received = payload, true
+		cancel()
+	})
+	require.True(t, received, "no event published")
+	return res
+}
+
+func TestJumpToEvents(t *testing.T) {
+	t.Parallel()
+
+	slippage := binance.SlippageEstimate{
+		BestBid:  decimal.NewFromInt(3000),
+		SellVWAP: decimal.NewFromInt(3000),
+		BestAsk:  decimal.NewFromInt(60000),
+		BuyVWAP:  decimal.NewFromInt(60000),
+	}
+	pair := model.Pair{FromCoin: "ETH", ToCoin: "BTC"}
+
+	t.Run("completed", func(t *testing.T) {
+		t.Parallel()
+
+		finder, _ := newStubJumpFinder(t)
+		completed := eventbus.Subscribe(finder.EventBus, process.JumpCompleted)
+		failed := eventbus.Subscribe(finder.EventBus, process.JumpFailed)
+
+		require.NoError(t, finder.JumpTo(context.Background(), pair, slippage, true))
+
+		jump := firstEvent(t, completed).Jump
+		assert.Equal(t, "ETH", jump.FromCoin)
+		assert.Equal(t, "BTC", jump.ToCoin)
+		assert.Equal(t, "2", jump.FromQuantity.String())
+		assert.Equal(t, "3000", jump.FromPrice.String())
+		assert.Equal(t, "0.1", jump.ToQuantity.String())
+		assert.Equal(t, "60000", jump.ToPrice.String())
+		assert.True(t, jump.Manual)
+		assert.Zero(t, failed.Stats().Pending)
+
+		current, _, err := finder.Repository.GetCurrentCoin()
+		require.NoError(t, err)
+		assert.Equal(t, "BTC", current.Coin)
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		t.Parallel()
+
+		finder, server := newStubJumpFinder(t)
+		server.RejectOrders = "Account has insufficient balance for requested action."
+		completed := eventbus.Subscribe(finder.EventBus, process.JumpCompleted)
+		failed := eventbus.Subscribe(finder.EventBus, process.JumpFailed)
+
+		require.Error(t, finder.JumpTo(context.Background(), pair, slippage, false))
+
+		failure := firstEvent(t, failed)
+		assert.Equal(t, "ETH", failure.FromCoin)
+		assert.Equal(t, "BTC", failure.ToCoin)
+		assert.False(t, failure.Manual)
+		assert.Contains(t, failure.Reason, "insufficient balance")
+		assert.Zero(t, completed.Stats().Pending)
+
+		current, _, err := finder.Repository.GetCurrentCoin()
+		require.NoError(t, err)
+		assert.Equal(t, "ETH", current.Coin, "still on the coin it failed to sell")
+	})
+}
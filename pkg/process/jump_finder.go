@@ -2,7 +2,10 @@ package process
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/shopspring/decimal"
@@ -14,43 +17,74 @@ import (
 	"github.com/erwanlbp/trading-bot/pkg/log"
 	"github.com/erwanlbp/trading-bot/pkg/model"
 	"github.com/erwanlbp/trading-bot/pkg/repository"
+	"github.com/erwanlbp/trading-bot/pkg/telegram"
 	"github.com/erwanlbp/trading-bot/pkg/util"
 )
 
 type JumpFinder struct {
-	Logger     *log.Logger
-	Binance    *binance.Client
-	Repository *repository.Repository
-	EventBus   *eventbus.Bus
-	ConfigFile *configfile.ConfigFile
+	Logger         *log.Logger
+	Binance        *binance.Client
+	Repository     *repository.Repository
+	EventBus       *eventbus.Bus
+	ConfigFile     *configfile.ConfigFile
+	CircuitBreaker *CircuitBreaker
+	TelegramClient *telegram.Client
+
+	// Last validated prices, to re-check a proposal when it's approved
+	lastCheck PriceCheck
+	mtx       sync.Mutex
 }
 
 func NewJumpFinder(l *log.Logger,
 	r *repository.Repository,
 	eb *eventbus.Bus,
 	cf *configfile.ConfigFile,
-	bc *binance.Client) *JumpFinder {
+	bc *binance.Client,
+	cb *CircuitBreaker,
+	tc *telegram.Client) *JumpFinder {
 	return &JumpFinder{
-		Logger:     l,
-		Repository: r,
-		EventBus:   eb,
-		ConfigFile: cf,
-		Binance:    bc,
+		Logger:         l,
+		Repository:     r,
+		EventBus:       eb,
+		ConfigFile:     cf,
+		Binance:        bc,
+		CircuitBreaker: cb,
+		TelegramClient: tc,
 	}
 }
 
 func (p *JumpFinder) Start(ctx context.Context) {
 
-	sub := p.EventBus.Subscribe(eventbus.EventCoinsPricesFetched)
+	sub := eventbus.SubscribeWith(p.EventBus, eventbus.LatestOnly, CoinsPricesValidated)
 
 	go sub.Handler(ctx, p.FindJump)
 }
 
-func (p *JumpFinder) FindJump(ctx context.Context, _ eventbus.Event) {
+func (p *JumpFinder) FindJump(ctx context.Context, check PriceCheck) {
 	logger := p.Logger.With(zap.String("process", "jump_finder"))
 
-	// Get pairsRatio from current prices
-	pairsRatio, err := p.CalculateRatios()
+	if p.CircuitBreaker.IsTripped() {
+		logger.Debug("Circuit breaker is tripped, waiting for /resume")
+		return
+	}
+	if paused, err := p.Repository.IsPaused(); err != nil {
+		logger.Error("Failed to get pause state", zap.Error(err))
+		return
+	} else if paused {
+		logger.Debug("Jumps are paused, waiting for /unpause")
+		return
+	}
+
+	if p.ConfigFile.Approval.Enabled {
+		p.ExpireProposals(logger)
+	}
+
+	p.mtx.Lock()
+	p.lastCheck = check
+	p.mtx.Unlock()
+
+	// Get pairsRatio from current prices, coins with a price anomaly are left out thus can't be part of a jump, illiquid ones can't be jumped to
+	pairsRatio, err := p.CalculateRatios(ctx, check)
 	if err != nil {
 		logger.Error("Failed to calculate new ratios, can't find better coin", zap.Error(err))
 		return
@@ -71,8 +105,20 @@ func (p *JumpFinder) FindJump(ctx context.Context, _ eventbus.Event) {
 		logger.Error("Failed getting current coin", zap.Error(err))
 		return
 	}
+	tradingAllowed := p.IsTradingAllowed(logger, check.Time())
+
 	// If we never jumped (first init) or something went wrong and we are now back to the bridge
 	if !hasEverJumped || currentCoin.Coin == p.ConfigFile.Bridge {
+		if !tradingAllowed {
+			return
+		}
+		if exited, err := p.Repository.HasManuallyExited(); err != nil {
+			logger.Error("Failed checking last jump", zap.Error(err))
+			return
+		} else if exited {
+			logger.Debug("Staying on the bridge after a manual exit, waiting for /enter")
+			return
+		}
 		if !hasEverJumped {
 			logger.Info("Never jumped before, will try to find a first coin")
 		} else {
@@ -82,7 +128,7 @@ func (p *JumpFinder) FindJump(ctx context.Context, _ eventbus.Event) {
 			logger.Error("Failed finding coin from bridge", zap.Error(err))
 			return
 		}
-		p.EventBus.Notify(eventbus.GenerateEvent(eventbus.SaveBalance, nil))
+		eventbus.Publish(p.EventBus, eventbus.SaveBalance, eventbus.NoPayload{})
 		return
 	}
 
@@ -92,12 +138,150 @@ func (p *JumpFinder) FindJump(ctx context.Context, _ eventbus.Event) {
 
 	logger.Debug(fmt.Sprintf("Need a gain of %s", wantedGain))
 
-	type BJ struct {
-		Pair model.PairWithTickerRatio
-		Diff decimal.Decimal
+	jumpsFrom, computedDiff := p.JumpCandidates(ctx, logger, pairsRatio, currentCoin.Coin, wantedGain)
+
+	eventbus.Publish(p.EventBus, JumpEvaluated, JumpEvaluation{
+		Timestamp:      time.Now().UTC(),
+		CurrentCoin:    currentCoin.Coin,
+		WantedGain:     wantedGain,
+		Diffs:          computedDiff,
+		TradingAllowed: tradingAllowed,
+	})
+
+	// Clean all data and savec new one to get info about next jump
+	err = p.Repository.ReplaceAllDiff(computedDiff)
+	if err != nil {
+		logger.Warn("Error while updating diff in DB", zap.Error(err))
+	}
+
+	// Diffs are saved anyway, to follow them on telegram
+	if !tradingAllowed {
+		return
+	}
+
+	var goodPaths []JumpPath
+	for _, path := range FindJumpPaths(currentCoin.Coin, jumpsFrom, p.ConfigFile.Jump.MaxHops) {
+		if path.Diff.LessThan(wantedGain) {
+			continue
+		}
+		// Direct jumps are already logged above
+		if len(path.Hops) > 1 {
+			logger.Info(fmt.Sprintf("✅ Path %s is good", path.LogSymbol()), zap.String("diff", path.Diff.String()), zap.String("threshold", wantedGain.String()))
+		}
+		goodPaths = append(goodPaths, path)
+	}
+
+	if len(goodPaths) == 0 {
+		logger.Debug(fmt.Sprintf("No jump found from coin %s", currentCoin.Coin))
+		return
+	}
+
+	if p.ConfigFile.Approval.Enabled {
+		pending, err := p.Repository.HasPendingJumpProposal()
+		if err != nil {
+			logger.Error("Failed to check pending jump proposals", zap.Error(err))
+			return
+		}
+		if pending {
+			logger.Debug("A jump proposal is waiting for approval, not proposing another one")
+			return
+		}
+	}
+
+	// Best diff first, the first one still good once the expected slippage is removed will be the one
+	sort.SliceStable(goodPaths, func(i, j int) bool {
+		return goodPaths[i].Diff.GreaterThan(goodPaths[j].Diff)
+	})
+
+	balances, err := p.Binance.GetBalance(ctx, currentCoin.Coin)
+	if err != nil {
+		logger.Error("Failed to get current coin balance, can't estimate slippage", zap.Error(err))
+		return
+	}
+
+	var bestPath *JumpPath
+	var bestPathSlippages []binance.SlippageEstimate
+	var bestPathSlippageMultiplier decimal.Decimal
+	for _, path := range goodPaths {
+		estimates, slippageMultiplier, err := p.EstimatePathSlippage(ctx, path, balances[currentCoin.Coin])
+		if err != nil {
+			logger.Warn(fmt.Sprintf("Failed to estimate slippage for path %s, ignoring it", path.LogSymbol()), zap.Error(err))
+			continue
+		}
+
+		netDiff := path.Diff.Mul(slippageMultiplier)
+		if netDiff.LessThan(wantedGain) {
+			logger.Info(fmt.Sprintf("❌ Path %s is not good anymore with expected slippage", path.LogSymbol()), zap.String("diff", path.Diff.String()), zap.String("slippage", decimal.NewFromInt(1).Sub(slippageMultiplier).String()), zap.String("net_diff", netDiff.String()), zap.String("threshold", wantedGain.String()))
+			continue
+		}
+
+		bestPath = util.WrapPtr(path)
+		bestPathSlippages = estimates
+		bestPathSlippageMultiplier = slippageMultiplier
+		break
+	}
+
+	if bestPath == nil {
+		logger.Debug(fmt.Sprintf("No jump found from coin %s once slippage removed", currentCoin.Coin))
+		return
+	}
+
+	if len(bestPath.Hops) > 1 {
+		logger.Info(fmt.Sprintf("Chose path %s", bestPath.LogSymbol()), zap.String("diff", bestPath.Diff.String()))
+	}
+
+	eventbus.Publish(p.EventBus, JumpDecided, JumpDecision{
+		Timestamp:        time.Now().UTC(),
+		Path:             bestPath.Coins(),
+		Diff:             bestPath.Diff.Mul(bestPathSlippageMultiplier),
+		WantedGain:       wantedGain,
+		FromQuantity:     balances[currentCoin.Coin],
+		ExpectedSlippage: decimal.NewFromInt(1).Sub(bestPathSlippageMultiplier),
+		PendingApproval:  p.ConfigFile.Approval.Enabled,
+	})
+
+	if p.ConfigFile.Approval.Enabled {
+		if err := p.ProposeJump(*bestPath, wantedGain, balances[currentCoin.Coin], bestPathSlippages); err != nil {
+			logger.Error("Failed to propose jump", zap.Error(err))
+		}
+		return
+	}
+
+	if err := p.ExecutePath(ctx, *bestPath, bestPathSlippages); err != nil {
+		if len(bestPath.Hops) == 1 && errors.Is(err, ErrPartialSell) {
+			logger.Info("Sell is partially executed, the rest will be sold on next tick", zap.Error(err))
+		} else {
+			logger.Error("Failed to jump", zap.Error(err))
+		}
 	}
 
-	var bestJump *BJ
+	eventbus.Publish(p.EventBus, eventbus.SaveBalance, eventbus.NoPayload{})
+}
+
+// False outside of trading windows or during a blackout at the given time, the tick time so a replay is checked at the recorded time
+func (p *JumpFinder) IsTradingAllowed(logger *zap.Logger, now time.Time) bool {
+	now = now.UTC()
+	if !p.ConfigFile.TradingWindows.IsOpen(now) {
+		logger.Debug("Not jumping, outside of trading windows, see /blackouts")
+		return false
+	}
+	blackout, found, err := p.Repository.GetCurrentBlackout(now)
+	if err != nil {
+		logger.Error("Not jumping, failed to get current blackout", zap.Error(err))
+		return false
+	}
+	if found {
+		logger.Debug(fmt.Sprintf("Not jumping, blackout #%d until %s", blackout.ID, blackout.EndsAt.Format(time.DateTime)))
+		return false
+	}
+	return true
+}
+
+// Compute the diff of all pairs, return the possible jumps indexed by from_coin, to find paths going through several coins.
+//
+// Pairs from currentCoin are logged, currentCoin can be empty to log nothing
+func (p *JumpFinder) JumpCandidates(ctx context.Context, logger *zap.Logger, pairsRatio []model.PairWithTickerRatio, currentCoin string, wantedGain decimal.Decimal) (map[string][]JumpCandidate, []model.Diff) {
+	jumpsFrom := make(map[string][]JumpCandidate)
 	var computedDiff []model.Diff
 	for _, pairRatio := range pairsRatio {
 
@@ -115,8 +299,9 @@ func (p *JumpFinder) FindJump(ctx context.Context, _ eventbus.Event) {
 				logger.Error(fmt.Sprintf("No default ratio found for pair %s, ignoring", pairRatio.Pair.LogSymbol()))
 				continue
 			}
-			logger.Info(fmt.Sprintf("Pair %s doesn't have a jump ratio yet, defaulting to last 15min avg %s", pairRatio.Pair.LogSymbol(), defaultRatio))
-			lastPairRatio = defaultRatio
+			// History has last prices ratios, jump ratios are on the bid/ask basis
+			lastPairRatio = pairRatio.WithSpread(defaultRatio)
+			logger.Info(fmt.Sprintf("Pair %s doesn't have a jump ratio yet, defaulting to last 15min avg %s", pairRatio.Pair.LogSymbol(), lastPairRatio))
 		}
 
 		feeMultiplier, err := p.Binance.GetJumpFeeMultiplier(ctx, pairRatio.Pair.FromCoin, pairRatio.Pair.ToCoin, p.ConfigFile.Bridge)
@@ -124,7 +309,7 @@ func (p *JumpFinder) FindJump(ctx context.Context, _ eventbus.Event) {
 			feeMultiplier = binance.DefaultFee
 		}
 
-		diff := feeMultiplier.Mul(pairRatio.Ratio).Div(lastPairRatio)
+		diff := feeMultiplier.Mul(pairRatio.ExecutableRatio).Div(lastPairRatio)
 
 		computedDiff = append(computedDiff, model.Diff{
 			FromCoin:   pairRatio.Pair.FromCoin,
@@ -134,53 +319,65 @@ func (p *JumpFinder) FindJump(ctx context.Context, _ eventbus.Event) {
 			NeededDiff: wantedGain,
 		})
 
-		if pairRatio.Pair.FromCoin != currentCoin.Coin {
+		jumpsFrom[pairRatio.Pair.FromCoin] = append(jumpsFrom[pairRatio.Pair.FromCoin], JumpCandidate{
+			Pair: pairRatio,
+			Diff: diff,
+			Fee:  feeMultiplier,
+		})
+
+		if pairRatio.Pair.FromCoin != currentCoin {
 			continue
 		}
 
 		if diff.LessThan(wantedGain) {
-			logger.Debug(fmt.Sprintf("❌ Pair %s is not good", pairRatio.Pair.LogSymbol()), zap.String("current_ratio", pairRatio.Ratio.String()), zap.String("last_jump_ratio", lastPairRatio.String()), zap.String("diff", diff.String()), zap.String("fee", feeMultiplier.String()), zap.String("threshold", wantedGain.String()))
+			logger.Debug(fmt.Sprintf("❌ Pair %s is not good", pairRatio.Pair.LogSymbol()), zap.String("current_ratio", pairRatio.ExecutableRatio.String()), zap.String("last_jump_ratio", lastPairRatio.String()), zap.String("diff", diff.String()), zap.String("fee", feeMultiplier.String()), zap.String("threshold", wantedGain.String()))
 			continue
 		}
 
-		logger.Info(fmt.Sprintf("✅ Pair %s is good", pairRatio.Pair.LogSymbol()), zap.String("current_ratio", pairRatio.Ratio.String()), zap.String("last_jump_ratio", lastPairRatio.String()), zap.String("diff", diff.String()), zap.String("fee", feeMultiplier.String()), zap.String("threshold", wantedGain.String()))
-
-		if bestJump == nil || bestJump.Diff.LessThan(diff) {
-			bestJump = &BJ{
-				Pair: pairRatio,
-				Diff: diff,
-			}
-		}
+		logger.Info(fmt.Sprintf("✅ Pair %s is good", pairRatio.Pair.LogSymbol()), zap.String("current_ratio", pairRatio.ExecutableRatio.String()), zap.String("last_jump_ratio", lastPairRatio.String()), zap.String("diff", diff.String()), zap.String("fee", feeMultiplier.String()), zap.String("threshold", wantedGain.String()))
 	}
 
-	// Clean all data and savec new one to get info about next jump
-	err = p.Repository.ReplaceAllDiff(computedDiff)
-	if err != nil {
-		logger.Warn("Error while updating diff in DB", zap.Error(err))
-	}
+	return jumpsFrom, computedDiff
+}
 
-	if bestJump == nil {
-		logger.Debug(fmt.Sprintf("No jump found from coin %s", currentCoin.Coin))
-		return
+// Jump through each hop of the path, stop at the first failure
+func (p *JumpFinder) ExecutePath(ctx context.Context, path JumpPath, slippages []binance.SlippageEstimate) error {
+	jump := func(ctx context.Context, pair model.Pair, slippage binance.SlippageEstimate) error {
+		return p.JumpTo(ctx, pair, slippage, false)
 	}
+	return RunPath(ctx, path, slippages, jump, p.CircuitBreaker.RecordJump)
+}
 
-	if err := p.JumpTo(ctx, bestJump.Pair.Pair); err != nil {
-		logger.Error("Failed to jump", zap.Error(err))
-	}
+// Estimate the slippage of each hop of the path, the quantity of each hop is the expected result of the previous one.
+//
+// Also return the multiplier to apply on the path diff to remove the slippage
+func (p *JumpFinder) EstimatePathSlippage(ctx context.Context, path JumpPath, quantity decimal.Decimal) ([]binance.SlippageEstimate, decimal.Decimal, error) {
+	multiplier := decimal.NewFromInt(1)
+	var estimates []binance.SlippageEstimate
+	for _, hop := range path.Hops {
+		estimate, err := p.Binance.EstimateJumpSlippage(ctx, hop.Pair.Pair.FromCoin, hop.Pair.Pair.ToCoin, p.ConfigFile.Bridge, quantity)
+		if err != nil {
+			return nil, decimal.Zero, fmt.Errorf("pair %s: %w", hop.Pair.Pair.LogSymbol(), err)
+		}
+		estimates = append(estimates, estimate)
+		multiplier = multiplier.Mul(decimal.NewFromInt(1).Sub(estimate.Slippage()))
 
-	p.EventBus.Notify(eventbus.GenerateEvent(eventbus.SaveBalance, nil))
+		if estimate.BuyVWAP.IsZero() {
+			return nil, decimal.Zero, fmt.Errorf("pair %s: no buy price", hop.Pair.Pair.LogSymbol())
+		}
+		quantity = quantity.Mul(estimate.SellVWAP).Div(estimate.BuyVWAP)
+	}
+	return estimates, multiplier, nil
 }
 
-func (p *JumpFinder) CalculateRatios() ([]model.PairWithTickerRatio, error) {
-	lastPrices, err := p.Repository.GetCoinsLastPrice(p.ConfigFile.Bridge)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get coins last price: %w", err)
-	}
+// Save the ratios of all pairs, flagged when a price has an anomaly, but only return the ones we could jump to: trusted prices, enabled and liquid to_coin
+func (p *JumpFinder) CalculateRatios(ctx context.Context, check PriceCheck) ([]model.PairWithTickerRatio, error) {
+	lastPrices := check.All
 	if len(lastPrices) == 0 {
 		return nil, nil
 	}
 
-	now := lastPrices[0].Timestamp
+	now := check.Time()
 
 	pairs, err := p.Repository.GetPairs(repository.ExistingPair())
 	if err != nil {
@@ -193,6 +390,8 @@ func (p *JumpFinder) CalculateRatios() ([]model.PairWithTickerRatio, error) {
 	}
 	enabledCoins := util.AsSet(ec, util.Identity[string]())
 
+	illiquidCoins := p.IlliquidCoins(ctx, ec)
+
 	var pairsHistory []model.PairHistory
 	var res []model.PairWithTickerRatio
 	for _, coinFromPrice := range lastPrices {
@@ -202,21 +401,29 @@ func (p *JumpFinder) CalculateRatios() ([]model.PairWithTickerRatio, error) {
 				continue
 			}
 
+			if !coinFromPrice.Price.IsPositive() || !coinToPrice.Price.IsPositive() {
+				continue
+			}
+
 			ratio := coinFromPrice.Price.Div(coinToPrice.Price)
+			anomaly := check.HasAnomaly(pair.FromCoin) || check.HasAnomaly(pair.ToCoin)
 
 			history := model.PairHistory{
 				PairID:    pair.ID,
 				Timestamp: now,
 				Ratio:     ratio,
+				Anomaly:   anomaly,
 			}
 			pairsHistory = append(pairsHistory, history)
 
 			// We only return pairs which have enabled to_coin, we don't want to jump to some disabled coin
-			if enabledCoins[pair.ToCoin] {
+			// nor park the balance in a coin we couldn't easily get out of. Prices with an anomaly can't be trusted to jump
+			if !anomaly && enabledCoins[pair.ToCoin] && illiquidCoins[pair.ToCoin] == "" {
 				res = append(res, model.PairWithTickerRatio{
-					Pair:      pair,
-					Ratio:     ratio,
-					Timestamp: now,
+					Pair:            pair,
+					Ratio:           ratio,
+					ExecutableRatio: coinFromPrice.SellPrice().Div(coinToPrice.BuyPrice()),
+					Timestamp:       now,
 				})
 			}
 		}
@@ -229,11 +436,43 @@ func (p *JumpFinder) CalculateRatios() ([]model.PairWithTickerRatio, error) {
 	return res, nil
 }
 
-func (p *JumpFinder) JumpTo(ctx context.Context, pair model.Pair) error {
-	release, err := p.Binance.TradeLock()
+// Coins that are not liquid enough to jump to, with the reason
+func (p *JumpFinder) IlliquidCoins(ctx context.Context, coins []string) map[string]string {
+	res := make(map[string]string)
+	for _, coin := range coins {
+		if reason := p.Binance.CheckCoinLiquidity(ctx, coin); reason != "" {
+			res[coin] = reason
+		}
+	}
+	return res
+}
+
+// Slippage estimate can be empty if unknown, then no slippage is saved on the jump
+func (p *JumpFinder) JumpTo(ctx context.Context, pair model.Pair, slippage binance.SlippageEstimate, manual bool) error {
+	jump, err := p.jumpTo(ctx, pair, slippage, manual)
 	if err != nil {
+		eventbus.Publish(p.EventBus, JumpFailed, JumpFailure{
+			Timestamp: time.Now().UTC(),
+			FromCoin:  pair.FromCoin,
+			ToCoin:    pair.ToCoin,
+			Manual:    manual,
+			Reason:    err.Error(),
+		})
 		return err
 	}
+
+	eventbus.Publish(p.EventBus, JumpCompleted, JumpResult{Jump: jump})
+	return nil
+}
+
+// We stay on the from coin with what's left, a direct jump will sell it on next tick
+var ErrPartialSell = errors.New("sell is partially executed")
+
+func (p *JumpFinder) jumpTo(ctx context.Context, pair model.Pair, slippage binance.SlippageEstimate, manual bool) (model.Jump, error) {
+	release, err := p.Binance.TradeLock()
+	if err != nil {
+		return model.Jump{}, err
+	}
 	defer release()
 
 	p.Logger.Info(fmt.Sprintf("Will jump from %s to %s", pair.FromCoin, pair.ToCoin))
@@ -246,10 +485,10 @@ func (p *JumpFinder) JumpTo(ctx context.Context, pair model.Pair) error {
 	if err != nil {
 		if sell.IsPartiallyExecuted() {
 			p.Logger.Warn(fmt.Sprintf("Sell is partially executed, thus we stay on %s and it will be all sold next jump", pair.FromCoin))
-			return nil
+			return model.Jump{}, ErrPartialSell
 		}
 		p.Logger.Error(fmt.Sprintf("Failed to sell %s", util.LogSymbol(pair.FromCoin, p.ConfigFile.Bridge)), zap.Error(err))
-		return err
+		return model.Jump{}, err
 	}
 	// In case something goes wrong afterward, save bridge as current coin
 	if _, err := p.Repository.SetCurrentCoin(p.ConfigFile.Bridge, sell.Time()); err != nil {
@@ -264,7 +503,7 @@ func (p *JumpFinder) JumpTo(ctx context.Context, pair model.Pair) error {
 			p.Logger.Warn(fmt.Sprintf("Buy is partially executed, thus we go on %s", pair.ToCoin))
 		} else {
 			p.Logger.Error(fmt.Sprintf("Failed to buy %s", util.LogSymbol(pair.ToCoin, p.ConfigFile.Bridge)), zap.Error(err))
-			return err
+			return model.Jump{}, err
 		}
 	}
 	p.Logger.Info("Bought " + pair.ToCoin)
@@ -279,18 +518,23 @@ func (p *JumpFinder) JumpTo(ctx context.Context, pair model.Pair) error {
 		FromQuantity: sell.Quantity(),
 		ToPrice:      buy.Price(),
 		ToQuantity:   buy.Quantity(),
+
+		ExpectedSlippage: slippage.Slippage(),
+		RealizedSlippage: slippage.RealizedSlippage(sell.AvgPrice(), buy.AvgPrice()),
+
+		Manual: manual,
 	}
 
 	if err := repository.SimpleUpsert(p.Repository.DB.DB, jump); err != nil {
-		return fmt.Errorf("failed to save jump")
+		return jump, fmt.Errorf("failed to save jump")
 	}
 	if err := p.UpdatePairsToCoinRatios(ctx, pair, &buy, &sell); err != nil {
 		p.Logger.Error(fmt.Sprintf("Failed to update pairs to coin %s ratios'", pair.ToCoin), zap.Error(err))
 		// TODO Not enough
-		return err
+		return jump, err
 	}
 
-	return nil
+	return jump, nil
 }
 
 // TODO The algo to find a "best" coin could be better lol it's kinda random right now I guess
@@ -311,7 +555,12 @@ func (p *JumpFinder) FindGoodCoinFromBridge(ctx context.Context, pairsRatio []mo
 	var bestPair *model.PairWithTickerRatio
 	var bestPairLastRatio model.PairHistory
 	var bestPairDiff decimal.Decimal
+	illiquidCoins := p.IlliquidCoins(ctx, util.Distinct(util.Map(pairsRatio, func(pr model.PairWithTickerRatio) string { return pr.Pair.FromCoin })))
 	for _, currentRatio := range pairsRatio {
+		// The from_coin is the one bought, it must be liquid too
+		if illiquidCoins[currentRatio.Pair.FromCoin] != "" {
+			continue
+		}
 		for _, lastRatio := range lastRatios {
 			if currentRatio.Pair.ID != lastRatio.PairID {
 				continue
@@ -384,19 +633,30 @@ func (p *JumpFinder) FindGoodCoinFromBridge(ctx context.Context, pairsRatio []mo
 
 	logger.Info(fmt.Sprintf("Best pair from bridge is %s, thus will buy %s", bestPair.Pair.LogSymbol(), bestCoin), zap.String("diff", bestPairDiff.String()), zap.Duration("last_pair_refresh", bestPair.Timestamp.Sub(bestPairLastRatio.Timestamp)))
 
-	buy, err := p.Binance.Buy(ctx, bestCoin, p.ConfigFile.Bridge)
+	_, err = p.EnterFromBridge(ctx, bestCoin)
+	return err
+}
+
+// Buy the coin with the bridge and make it the current coin, the trade lock must be held
+func (p *JumpFinder) EnterFromBridge(ctx context.Context, coin string) (binance.OrderResult, error) {
+	buy, err := p.Binance.Buy(ctx, coin, p.ConfigFile.Bridge)
 	if err != nil {
-		logger.Error(fmt.Sprintf("Failed to buy %s", util.LogSymbol(bestCoin, p.ConfigFile.Bridge)), zap.Error(err))
-		return err
+		p.Logger.Error(fmt.Sprintf("Failed to buy %s", util.LogSymbol(coin, p.ConfigFile.Bridge)), zap.Error(err))
+		return buy, err
 	}
 
-	if err := p.UpdatePairsToCoinRatios(ctx, model.Pair{ToCoin: bestCoin}, &buy, nil); err != nil {
-		logger.Error(fmt.Sprintf("Failed to update pairs to coin %s ratios'", bestCoin), zap.Error(err))
+	if err := p.UpdatePairsToCoinRatios(ctx, model.Pair{ToCoin: coin}, &buy, nil); err != nil {
+		p.Logger.Error(fmt.Sprintf("Failed to update pairs to coin %s ratios'", coin), zap.Error(err))
 		// TODO Not enough
-		return err
+		return buy, err
 	}
 
-	return nil
+	// Entering from the bridge is the new reference to take profit
+	if err := p.Repository.SetPositionReference(buy.Quantity().Mul(buy.AvgPrice()), buy.Time()); err != nil {
+		p.Logger.Error("Failed to save take profit reference", zap.Error(err))
+	}
+
+	return buy, nil
 }
 
 func (p *JumpFinder) UpdatePairsToCoinRatios(ctx context.Context, pair model.Pair, buy, sell *binance.OrderResult) error {
@@ -414,7 +674,13 @@ func (p *JumpFinder) UpdatePairsToCoinRatios(ctx context.Context, pair model.Pai
 	if err != nil {
 		return fmt.Errorf("failed to get prices for pair to new current_coin: %w", err)
 	}
+	// Not blocking, we'll fall back on last prices
+	tickers, err := p.Binance.GetCoinsBookTicker(ctx, fromCoins, []string{p.ConfigFile.Bridge})
+	if err != nil {
+		p.Logger.Warn("Failed to get book tickers, ratios to new current_coin will be based on last prices", zap.Error(err))
+	}
 
+	// Ratios are on the bid/ask basis the jump finder compares them to: selling from_coin at bid, buying to_coin at ask (the buy price)
 	var pairsToSave []model.Pair
 	for _, pa := range pairs {
 		if pair.FromCoin == pa.FromCoin && pair.ToCoin == pa.ToCoin {
@@ -422,7 +688,12 @@ func (p *JumpFinder) UpdatePairsToCoinRatios(ctx context.Context, pair model.Pai
 			pa.LastJumpRatio = sell.Price().Div(buy.Price())
 			pa.LastJumpRatioBasedOn = pa.LastJump
 		} else {
-			pa.LastJumpRatio = prices[util.Symbol(pa.FromCoin, p.ConfigFile.Bridge)].Price.Div(buy.Price())
+			symbol := util.Symbol(pa.FromCoin, p.ConfigFile.Bridge)
+			fromPrice := prices[symbol].Price
+			if bid := tickers[symbol].BidPrice; bid.IsPositive() {
+				fromPrice = bid
+			}
+			pa.LastJumpRatio = fromPrice.Div(buy.Price())
 			pa.LastJumpRatioBasedOn = buy.Time()
 		}
 		pairsToSave = append(pairsToSave, pa)
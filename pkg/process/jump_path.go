@@ -0,0 +1,124 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/erwanlbp/trading-bot/pkg/binance"
+	"github.com/erwanlbp/trading-bot/pkg/model"
+)
+
+type JumpCandidate struct {
+	Pair model.PairWithTickerRatio
+	Diff decimal.Decimal
+	Fee  decimal.Decimal
+}
+
+// Sequence of jumps, its diff is the product of each hop diff, thus fees are compounded
+type JumpPath struct {
+	Hops []JumpCandidate
+	Diff decimal.Decimal
+}
+
+func (p JumpPath) Coins() []string {
+	if len(p.Hops) == 0 {
+		return nil
+	}
+	coins := []string{p.Hops[0].Pair.Pair.FromCoin}
+	for _, hop := range p.Hops {
+		coins = append(coins, hop.Pair.Pair.ToCoin)
+	}
+	return coins
+}
+
+func (p JumpPath) LogSymbol() string {
+	return strings.Join(p.Coins(), " → ")
+}
+
+// Fee multiplier of the whole path
+func (p JumpPath) Fee() decimal.Decimal {
+	res := decimal.NewFromInt(1)
+	for _, hop := range p.Hops {
+		res = res.Mul(hop.Fee)
+	}
+	return res
+}
+
+// Build back the path going through these coins, false if one of the jumps isn't possible
+func PathThrough(coins []string, jumpsFrom map[string][]JumpCandidate) (JumpPath, bool) {
+	path := JumpPath{Diff: decimal.NewFromInt(1)}
+	for i := 1; i < len(coins); i++ {
+		found := false
+		for _, jump := range jumpsFrom[coins[i-1]] {
+			if jump.Pair.Pair.ToCoin == coins[i] {
+				path.Hops = append(path.Hops, jump)
+				path.Diff = path.Diff.Mul(jump.Diff)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return JumpPath{}, false
+		}
+	}
+	return path, len(path.Hops) > 0
+}
+
+// All paths starting from a coin, from 1 to maxHops jumps, never going twice through the same coin.
+//
+// jumpsFrom contains the possible jumps, indexed by from_coin
+func FindJumpPaths(from string, jumpsFrom map[string][]JumpCandidate, maxHops int) []JumpPath {
+	var res []JumpPath
+
+	var walk func(path JumpPath, visited map[string]bool)
+	walk = func(path JumpPath, visited map[string]bool) {
+		if len(path.Hops) == maxHops {
+			return
+		}
+		coin := from
+		if len(path.Hops) > 0 {
+			coin = path.Hops[len(path.Hops)-1].Pair.Pair.ToCoin
+		}
+		for _, jump := range jumpsFrom[coin] {
+			if visited[jump.Pair.Pair.ToCoin] {
+				continue
+			}
+			next := JumpPath{
+				Hops: append(append([]JumpCandidate{}, path.Hops...), jump),
+				Diff: path.Diff.Mul(jump.Diff),
+			}
+			res = append(res, next)
+
+			visited[jump.Pair.Pair.ToCoin] = true
+			walk(next, visited)
+			delete(visited, jump.Pair.Pair.ToCoin)
+		}
+	}
+	walk(JumpPath{Diff: decimal.NewFromInt(1)}, map[string]bool{from: true})
+
+	return res
+}
+
+type HopJumper func(ctx context.Context, pair model.Pair, slippage binance.SlippageEstimate) error
+
+// Jump each hop of the path in turn, stop at the first failure. Each hop result is given to record, for the circuit breaker.
+//
+// A partial sell on a direct jump is not recorded: we stay on the coin and the rest is sold on next tick.
+// On a longer path it's a failure, the next hop would sell a coin we didn't buy
+func RunPath(ctx context.Context, path JumpPath, slippages []binance.SlippageEstimate, jump HopJumper, record func(context.Context, error)) error {
+	for i, hop := range path.Hops {
+		err := jump(ctx, hop.Pair.Pair, slippages[i])
+		if len(path.Hops) == 1 && errors.Is(err, ErrPartialSell) {
+			return err
+		}
+		record(ctx, err)
+		if err != nil {
+			return fmt.Errorf("failed to jump %s: %w", hop.Pair.Pair.LogSymbol(), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package process_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/erwanlbp/trading-bot/pkg/binance"
+	"github.com/erwanlbp/trading-bot/pkg/model"
+	"github.com/erwanlbp/trading-bot/pkg/process"
+)
+
+func candidate(from, to string, diff float64) process.JumpCandidate {
+	return process.JumpCandidate{
+		Pair: model.PairWithTickerRatio{Pair: model.Pair{FromCoin: from, ToCoin: to}},
+		Diff: decimal.NewFromFloat(diff),
+	}
+}
+
+func TestFindJumpPaths(t *testing.T) {
+	t.Parallel()
+
+	jumpsFrom := map[string][]process.JumpCandidate{
+		"A": {candidate("A", "B", 1.01)},
+		"B": {candidate("B", "A", 1.5), candidate("B", "C", 1.1)},
+		"C": {candidate("C", "A", 2), candidate("C", "B", 2)},
+	}
+
+	for _, c := range []struct {
+		name     string
+		maxHops  int
+		expected map[string]string
+	}{
+		{
+			name:     "direct only",
+			maxHops:  1,
+			expected: map[string]string{"A → B": "1.01"},
+		},
+		{
+			name:     "two hops",
+			maxHops:  2,
+			expected: map[string]string{"A → B": "1.01", "A → B → C": "1.111"},
+		},
+		{
+			name:     "never twice the same coin",
+			maxHops:  5,
+			expected: map[string]string{"A → B": "1.01", "A → B → C": "1.111"},
+		},
+	} {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual := make(map[string]string)
+			for _, path := range process.FindJumpPaths("A", jumpsFrom, c.maxHops) {
+				actual[path.LogSymbol()] = path.Diff.String()
+			}
+
+			assert.Equal(t, c.expected, actual)
+		})
+	}
+}
+
+func TestRunPathPartialSell(t *testing.T) {
+	t.Parallel()
+
+	jumpsFrom := map[string][]process.JumpCandidate{
+		"A": {candidate("A", "B", 1.1)},
+		"B": {candidate("B", "C", 1.1)},
+	}
+
+	for _, c := range []struct {
+		name     string
+		coins    []string
+		jumped   []string
+		recorded []error
+	}{
+		{
+			// The next hop would sell B, that we didn't buy
+			name:     "two hops stop and record a failure",
+			coins:    []string{"A", "B", "C"},
+			jumped:   []string{"A/B"},
+			recorded: []error{process.ErrPartialSell},
+		},
+		{
+			// We stay on A, the rest is sold on next tick
+			name:   "direct jump is not a failure",
+			coins:  []string{"A", "B"},
+			jumped: []string{"A/B"},
+		},
+	} {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			path, ok := process.PathThrough(c.coins, jumpsFrom)
+			require.True(t, ok)
+
+			var jumped []string
+			jump := func(_ context.Context, pair model.Pair, _ binance.SlippageEstimate) error {
+				jumped = append(jumped, pair.LogSymbol())
+				return process.ErrPartialSell
+			}
+			var recorded []error
+			record := func(_ context.Context, err error) { recorded = append(recorded, err) }
+
+			err := process.RunPath(context.Background(), path, make([]binance.SlippageEstimate, len(path.Hops)), jump, record)
+			assert.ErrorIs(t, err, process.ErrPartialSell)
+			assert.Equal(t, c.jumped, jumped)
+			assert.Equal(t, c.recorded, recorded)
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package process
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prprprus/scheduler"
+	"go.uber.org/zap"
+
+	"github.com/erwanlbp/trading-bot/pkg/config/configfile"
+	"github.com/erwanlbp/trading-bot/pkg/log"
+	"github.com/erwanlbp/trading-bot/pkg/model"
+	"github.com/erwanlbp/trading-bot/pkg/repository"
+	"github.com/erwanlbp/trading-bot/pkg/service"
+	"github.com/erwanlbp/trading-bot/pkg/util"
+)
+
+// Disables the coins that kept losing value compared to the other coins while we held them, and enables them again after the cooldown
+type LosingCoinGuard struct {
+	Logger     *log.Logger
+	Repository *repository.Repository
+	ConfigFile *configfile.ConfigFile
+	Service    *service.Service
+}
+
+func NewLosingCoinGuard(l *log.Logger, r *repository.Repository, cf *configfile.ConfigFile, s *service.Service) *LosingCoinGuard {
+	return &LosingCoinGuard{
+		Logger:     l,
+		Repository: r,
+		ConfigFile: cf,
+		Service:    s,
+	}
+}
+
+func (p *LosingCoinGuard) Start(ctx context.Context) {
+	go func() {
+
+		Scheduler, _ := scheduler.NewScheduler(1000)
+
+		id := Scheduler.Every().Second(45).Do(p.CheckCoins)
+
+		// If ctx is canceled, we'll stop the job
+		<-ctx.Done()
+
+		if err := Scheduler.CancelJob(id); err != nil {
+			p.Logger.Error("failed canceling job", zap.Error(err))
+		}
+	}()
+}
+
+func (p *LosingCoinGuard) CheckCoins() {
+	logger := p.Logger.With(zap.String("process", "losing_coin_guard"))
+
+	suspensions, err := p.Repository.GetCoinSuspensions()
+	if err != nil {
+		logger.Error("Failed to get coin suspensions", zap.Error(err))
+		return
+	}
+	enabledCoins, err := p.Repository.GetEnabledCoins()
+	if err != nil {
+		logger.Error("Failed to get enabled coins", zap.Error(err))
+		return
+	}
+	enabled := util.AsSet(enabledCoins, util.Identity[string]())
+
+	now := time.Now().UTC()
+	for coin, suspension := range suspensions {
+		if !suspension.Active {
+			continue
+		}
+		inConfig := util.Exists(p.ConfigFile.Coins, func(c string) bool { return c == coin })
+		if now.Before(suspension.Until) {
+			// A config reload enables all the coins of the config
+			if enabled[coin] {
+				if err := p.Repository.DisableCoin(coin); err != nil {
+					logger.Error("Failed to disable suspended coin "+coin, zap.Error(err))
+				}
+				delete(enabled, coin)
+			}
+			continue
+		}
+		if err := p.Repository.EndCoinSuspension(coin, inConfig); err != nil {
+			logger.Error("Failed to end suspension of "+coin, zap.Error(err))
+			continue
+		}
+		if inConfig {
+			p.Logger.Info(fmt.Sprintf("✅ %s is enabled again after its cooldown", coin))
+		}
+	}
+
+	conf := p.ConfigFile.LosingCoins
+	if !conf.Enabled {
+		return
+	}
+
+	byCoin, err := p.Service.GetLastHoldings(conf.Holdings)
+	if err != nil {
+		logger.Error("Failed to get last holdings", zap.Error(err))
+		return
+	}
+
+	for coin := range enabled {
+		holdings := model.LastHoldings(byCoin[coin], suspensions[coin].Until, conf.Holdings)
+		if len(holdings) < conf.Holdings {
+			continue
+		}
+		avg := model.AvgResult(holdings)
+		if avg.GreaterThanOrEqual(conf.MinAvgResult) {
+			continue
+		}
+
+		reason := fmt.Sprintf("average result %s %% compared to the other coins over its last %d holdings", avg.StringFixed(2), len(holdings))
+		until := now.Add(conf.Cooldown)
+		if err := p.Repository.SuspendCoin(coin, reason, now, until); err != nil {
+			logger.Error("Failed to suspend "+coin, zap.Error(err))
+			continue
+		}
+		p.Logger.Warn(fmt.Sprintf("📉 %s is disabled until %s: %s", coin, until.Format(time.DateTime), reason))
+	}
+}
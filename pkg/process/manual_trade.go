@@ -0,0 +1,156 @@
+package process
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/erwanlbp/trading-bot/pkg/binance"
+	"github.com/erwanlbp/trading-bot/pkg/eventbus"
+	"github.com/erwanlbp/trading-bot/pkg/model"
+	"github.com/erwanlbp/trading-bot/pkg/repository"
+	"github.com/erwanlbp/trading-bot/pkg/util"
+)
+
+// Jumps asked on telegram, they go through the same path as the bot's jumps but are flagged as manual
+
+func (p *JumpFinder) ManualJumpTo(ctx context.Context, coin string) error {
+	currentCoin, hasEverJumped, err := p.Repository.GetCurrentCoin()
+	if err != nil {
+		return fmt.Errorf("failed getting current coin: %w", err)
+	}
+	if !hasEverJumped || currentCoin.Coin == p.ConfigFile.Bridge {
+		return fmt.Errorf("current coin is the bridge, use /enter")
+	}
+	if currentCoin.Coin == coin {
+		return fmt.Errorf("already on %s", coin)
+	}
+	if err := p.checkEnabledCoin(coin); err != nil {
+		return err
+	}
+
+	pairs, err := p.Repository.GetPairs(repository.ToCoin(coin))
+	if err != nil {
+		return fmt.Errorf("failed to get pairs: %w", err)
+	}
+	pair, exists := pairs[util.Symbol(currentCoin.Coin, coin)]
+	if !exists {
+		return fmt.Errorf("no pair %s", util.LogSymbol(currentCoin.Coin, coin))
+	}
+
+	// Only to be saved on the jump, we jump anyway
+	var slippage binance.SlippageEstimate
+	if balances, err := p.Binance.GetBalance(ctx, currentCoin.Coin); err == nil {
+		if estimate, err := p.Binance.EstimateJumpSlippage(ctx, currentCoin.Coin, coin, p.ConfigFile.Bridge, balances[currentCoin.Coin]); err == nil {
+			slippage = estimate
+		}
+	}
+
+	p.Logger.Info(fmt.Sprintf("✋ Manual jump asked to %s", coin))
+
+	if err := p.JumpTo(ctx, pair, slippage, true); err != nil {
+		return err
+	}
+
+	eventbus.Publish(p.EventBus, eventbus.SaveBalance, eventbus.NoPayload{})
+	return nil
+}
+
+// Sell the current coin to the bridge, the bot will then stay on the bridge until a manual entry
+func (p *JumpFinder) ManualExitToBridge(ctx context.Context) error {
+	release, err := p.Binance.TradeLock()
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	currentCoin, hasEverJumped, err := p.Repository.GetCurrentCoin()
+	if err != nil {
+		return fmt.Errorf("failed getting current coin: %w", err)
+	}
+	if !hasEverJumped || currentCoin.Coin == p.ConfigFile.Bridge {
+		return fmt.Errorf("already on the bridge")
+	}
+
+	p.Logger.Info(fmt.Sprintf("✋ Manual exit asked from %s to %s", currentCoin.Coin, p.ConfigFile.Bridge))
+
+	sell, err := p.Binance.Sell(ctx, currentCoin.Coin, p.ConfigFile.Bridge)
+	if err != nil {
+		return fmt.Errorf("failed to sell %s: %w", util.LogSymbol(currentCoin.Coin, p.ConfigFile.Bridge), err)
+	}
+	if _, err := p.Repository.SetCurrentCoin(p.ConfigFile.Bridge, sell.Time()); err != nil {
+		return fmt.Errorf("failed to set current coin to bridge: %w", err)
+	}
+
+	trade := model.ManualTrade{
+		Timestamp:      sell.Time(),
+		Side:           model.ManualExit,
+		Coin:           currentCoin.Coin,
+		Bridge:         p.ConfigFile.Bridge,
+		Price:          sell.Price(),
+		Quantity:       sell.Quantity(),
+		BridgeQuantity: sell.Quantity().Mul(sell.AvgPrice()),
+	}
+	if err := p.Repository.SaveManualTrade(trade); err != nil {
+		return fmt.Errorf("failed to save manual exit: %w", err)
+	}
+
+	p.Binance.LogBalances(ctx)
+	eventbus.Publish(p.EventBus, eventbus.SaveBalance, eventbus.NoPayload{})
+
+	return nil
+}
+
+func (p *JumpFinder) ManualEnter(ctx context.Context, coin string) error {
+	if err := p.checkEnabledCoin(coin); err != nil {
+		return err
+	}
+
+	release, err := p.Binance.TradeLock()
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	currentCoin, hasEverJumped, err := p.Repository.GetCurrentCoin()
+	if err != nil {
+		return fmt.Errorf("failed getting current coin: %w", err)
+	}
+	if hasEverJumped && currentCoin.Coin != p.ConfigFile.Bridge {
+		return fmt.Errorf("current coin is %s, use /jump_to", currentCoin.Coin)
+	}
+
+	p.Logger.Info(fmt.Sprintf("✋ Manual entry asked on %s", coin))
+
+	buy, err := p.EnterFromBridge(ctx, coin)
+	if err != nil {
+		return err
+	}
+
+	trade := model.ManualTrade{
+		Timestamp:      buy.Time(),
+		Side:           model.ManualEntry,
+		Coin:           coin,
+		Bridge:         p.ConfigFile.Bridge,
+		Price:          buy.Price(),
+		Quantity:       buy.Quantity(),
+		BridgeQuantity: buy.Quantity().Mul(buy.AvgPrice()),
+	}
+	if err := p.Repository.SaveManualTrade(trade); err != nil {
+		return fmt.Errorf("failed to save manual entry: %w", err)
+	}
+
+	eventbus.Publish(p.EventBus, eventbus.SaveBalance, eventbus.NoPayload{})
+
+	return nil
+}
+
+func (p *JumpFinder) checkEnabledCoin(coin string) error {
+	enabledCoins, err := p.Repository.GetEnabledCoins()
+	if err != nil {
+		return fmt.Errorf("failed to get enabled coins: %w", err)
+	}
+	if !util.Exists(enabledCoins, func(c string) bool { return c == coin }) {
+		return fmt.Errorf("%s is not an enabled coin", coin)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package process
+
+import (
+	"context"
+	"time"
+
+	"github.com/prprprus/scheduler"
+	"go.uber.org/zap"
+
+	"github.com/erwanlbp/trading-bot/pkg/config/configfile"
+	"github.com/erwanlbp/trading-bot/pkg/log"
+	"github.com/erwanlbp/trading-bot/pkg/repository"
+	"github.com/erwanlbp/trading-bot/pkg/util"
+)
+
+// Ends timed pauses and reminds that jumps are paused
+type PauseWatcher struct {
+	Logger     *log.Logger
+	Repository *repository.Repository
+	ConfigFile *configfile.ConfigFile
+}
+
+func NewPauseWatcher(l *log.Logger, r *repository.Repository, cf *configfile.ConfigFile) *PauseWatcher {
+	return &PauseWatcher{
+		Logger:     l,
+		Repository: r,
+		ConfigFile: cf,
+	}
+}
+
+func (p *PauseWatcher) Start(ctx context.Context) {
+	go func() {
+
+		Scheduler, _ := scheduler.NewScheduler(1000)
+
+		id := Scheduler.Every().Second(15).Do(p.CheckPause)
+
+		// If ctx is canceled, we'll stop the job
+		<-ctx.Done()
+
+		if err := Scheduler.CancelJob(id); err != nil {
+			p.Logger.Error("failed canceling job", zap.Error(err))
+		}
+	}()
+}
+
+func (p *PauseWatcher) CheckPause() {
+	pause, err := p.Repository.GetPause()
+	if err != nil {
+		p.Logger.Error("Failed to get pause state", zap.Error(err))
+		return
+	}
+	if !pause.Paused {
+		return
+	}
+
+	now := time.Now().UTC()
+
+	if !pause.IsActive(now) {
+		if err := p.Repository.Unpause(); err != nil {
+			p.Logger.Error("Failed to end pause", zap.Error(err))
+			return
+		}
+		p.Logger.Info("▶️ Pause is over, jumps are back")
+		return
+	}
+
+	if now.Sub(util.MaxTime(pause.PausedOn, pause.LastReminder)) < p.ConfigFile.PauseReminder {
+		return
+	}
+	if err := p.Repository.SetPauseReminder(now); err != nil {
+		p.Logger.Error("Failed to save pause reminder", zap.Error(err))
+		return
+	}
+	p.Logger.Info("⏸️ Reminder: " + pause.Description())
+}
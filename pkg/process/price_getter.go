@@ -74,12 +74,20 @@ func (p *PriceGetter) FetchCoinsPrices(ctx context.Context) {
 		return
 	}
 
+	// Not blocking, we'll fall back on last prices to calculate ratios
+	bookTickers, err := p.BinanceClient.GetCoinsBookTicker(ctx, coins, p.AltCoins)
+	if err != nil {
+		logger.Warn("Failed to get coins book tickers, ratios will be based on last prices", zap.Error(err))
+	}
+
 	var models []model.CoinPrice
-	for _, coinPrice := range prices {
+	for symbol, coinPrice := range prices {
 		models = append(models, model.CoinPrice{
 			Coin:      coinPrice.Coin,
 			AltCoin:   coinPrice.AltCoin,
 			Price:     coinPrice.Price,
+			BidPrice:  bookTickers[symbol].BidPrice,
+			AskPrice:  bookTickers[symbol].AskPrice,
 			Timestamp: coinPrice.Timestamp,
 		})
 	}
@@ -88,5 +96,5 @@ func (p *PriceGetter) FetchCoinsPrices(ctx context.Context) {
 		logger.Error("Failed to save coin prices", zap.Error(err))
 	}
 
-	p.EventBus.Notify(eventbus.GenerateEvent(eventbus.EventCoinsPricesFetched, nil))
+	eventbus.Publish(p.EventBus, eventbus.CoinsPricesFetched, eventbus.NoPayload{})
 }
@@ -0,0 +1,235 @@
+package process
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+
+	"github.com/erwanlbp/trading-bot/pkg/config/configfile"
+	"github.com/erwanlbp/trading-bot/pkg/eventbus"
+	"github.com/erwanlbp/trading-bot/pkg/log"
+	"github.com/erwanlbp/trading-bot/pkg/model"
+	"github.com/erwanlbp/trading-bot/pkg/repository"
+	"github.com/erwanlbp/trading-bot/pkg/util"
+)
+
+type PriceAnomaly struct {
+	Coin   string
+	Reason string
+}
+
+// Published once the fetched prices are checked
+var CoinsPricesValidated = eventbus.NewTopic[PriceCheck]("coins_prices_validated")
+
+// Result of the prices validation, payload of the CoinsPricesValidated event
+type PriceCheck struct {
+	// Last prices, including the ones having an anomaly
+	All []model.CoinPrice
+	// Last prices, without the ones having an anomaly
+	Valid     []model.CoinPrice
+	Anomalies []PriceAnomaly
+}
+
+// Time of the tick, the most recent price as a stale one is older than the tick
+func (c PriceCheck) Time() time.Time {
+	var res time.Time
+	for _, price := range c.All {
+		res = util.MaxTime(res, price.Timestamp)
+	}
+	return res
+}
+
+func (c PriceCheck) HasAnomaly(coin string) bool {
+	for _, anomaly := range c.Anomalies {
+		if anomaly.Coin == coin {
+			return true
+		}
+	}
+	return false
+}
+
+// Sits between the price getter and the processes deciding on prices, drops stale or suspicious prices
+type PriceValidator struct {
+	Logger     *log.Logger
+	Repository *repository.Repository
+	EventBus   *eventbus.Bus
+	ConfigFile *configfile.ConfigFile
+
+	// Anomalies already reported, to only notify when it changes
+	reported map[string]string
+	mtx      sync.Mutex
+}
+
+func NewPriceValidator(l *log.Logger, r *repository.Repository, eb *eventbus.Bus, cf *configfile.ConfigFile) *PriceValidator {
+	return &PriceValidator{
+		Logger:     l,
+		Repository: r,
+		EventBus:   eb,
+		ConfigFile: cf,
+		reported:   make(map[string]string),
+	}
+}
+
+func (p *PriceValidator) Start(ctx context.Context) {
+
+	sub := eventbus.SubscribeWith(p.EventBus, eventbus.LatestOnly, eventbus.CoinsPricesFetched)
+
+	go sub.Handler(ctx, p.ValidatePrices)
+}
+
+func (p *PriceValidator) ValidatePrices(ctx context.Context, _ eventbus.NoPayload) {
+	logger := p.Logger.With(zap.String("process", "price_validator"))
+
+	lastPrices, err := p.Repository.GetCoinsLastPrice(p.ConfigFile.Bridge)
+	if err != nil {
+		logger.Error("Failed to get coins last price, can't validate them", zap.Error(err))
+		return
+	}
+
+	enabledCoins, err := p.Repository.GetEnabledCoins()
+	if err != nil {
+		logger.Error("Failed to get enabled coins, can't validate prices", zap.Error(err))
+		return
+	}
+
+	now := time.Now().UTC()
+	history, err := p.Repository.GetCoinPricesSince(enabledCoins, p.ConfigFile.Bridge, now.Add(-p.ConfigFile.PriceGuard.Window))
+	if err != nil {
+		logger.Error("Failed to get prices history, can't validate prices", zap.Error(err))
+		return
+	}
+
+	check := CheckPrices(lastPrices, history, enabledCoins, now, p.ConfigFile.PriceGuard)
+
+	p.ReportAnomalies(check.Anomalies)
+
+	eventbus.Publish(p.EventBus, CoinsPricesValidated, check)
+}
+
+func (p *PriceValidator) ReportAnomalies(anomalies []PriceAnomaly) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	current := make(map[string]string)
+	var news []string
+	for _, anomaly := range anomalies {
+		current[anomaly.Coin] = anomaly.Reason
+		if p.reported[anomaly.Coin] != anomaly.Reason {
+			news = append(news, fmt.Sprintf("%s: %s", anomaly.Coin, anomaly.Reason))
+		}
+	}
+
+	var back []string
+	for coin := range p.reported {
+		if _, ok := current[coin]; !ok {
+			back = append(back, coin)
+		}
+	}
+	p.reported = current
+
+	if len(news) > 0 {
+		sort.Strings(news)
+		p.Logger.Warn("⚠️ Price anomalies, these coins won't be used to jump:\n" + strings.Join(news, "\n"))
+	}
+	if len(back) > 0 {
+		sort.Strings(back)
+		p.Logger.Info("Prices are back to normal for " + strings.Join(back, ", "))
+	}
+}
+
+// Split the last prices between valid ones and anomalies.
+//
+// history must contain the prices of the window, it can include the last prices
+func CheckPrices(lastPrices, history []model.CoinPrice, enabledCoins []string, now time.Time, conf configfile.PriceGuard) PriceCheck {
+	res := PriceCheck{All: lastPrices}
+
+	byCoin := make(map[string][]model.CoinPrice)
+	for _, price := range history {
+		byCoin[price.Coin] = append(byCoin[price.Coin], price)
+	}
+
+	seen := make(map[string]bool)
+	for _, price := range lastPrices {
+		seen[price.Coin] = true
+
+		if reason := checkPrice(price, byCoin[price.Coin], now, conf); reason != "" {
+			res.Anomalies = append(res.Anomalies, PriceAnomaly{Coin: price.Coin, Reason: reason})
+			continue
+		}
+		res.Valid = append(res.Valid, price)
+	}
+
+	for _, coin := range enabledCoins {
+		if !seen[coin] {
+			res.Anomalies = append(res.Anomalies, PriceAnomaly{Coin: coin, Reason: "no price in current tick"})
+		}
+	}
+
+	return res
+}
+
+// Return why the price can't be trusted, empty if it can
+func checkPrice(price model.CoinPrice, history []model.CoinPrice, now time.Time, conf configfile.PriceGuard) string {
+	if !price.Price.IsPositive() {
+		return fmt.Sprintf("invalid price %s", price.Price)
+	}
+	if age := now.Sub(price.Timestamp); age > conf.MaxAge {
+		return fmt.Sprintf("stale price, %s old", age.Truncate(time.Second))
+	}
+	if price.BidPrice.IsPositive() && price.AskPrice.IsPositive() && price.BidPrice.GreaterThan(price.AskPrice) {
+		return fmt.Sprintf("crossed book, bid %s > ask %s", price.BidPrice, price.AskPrice)
+	}
+
+	// Previous prices, oldest first
+	var previous []decimal.Decimal
+	sort.Slice(history, func(i, j int) bool { return history[i].Timestamp.Before(history[j].Timestamp) })
+	for _, h := range history {
+		if h.Timestamp.Before(price.Timestamp) && h.Price.IsPositive() {
+			previous = append(previous, h.Price)
+		}
+	}
+	// Not enough history to know the usual moves
+	if len(previous) < 6 {
+		return ""
+	}
+
+	var returns []float64
+	for i := 1; i < len(previous); i++ {
+		returns = append(returns, previous[i].Div(previous[i-1]).Sub(decimal.NewFromInt(1)).InexactFloat64())
+	}
+	sigma := stdDev(returns)
+
+	move := price.Price.Div(previous[len(previous)-1]).Sub(decimal.NewFromInt(1)).InexactFloat64()
+	if math.Abs(move)*100 < conf.MinMove.InexactFloat64() {
+		return ""
+	}
+	if math.Abs(move) > conf.MaxSigma.InexactFloat64()*sigma {
+		return fmt.Sprintf("moved %.2f %% in one tick, usual move is %.2f %%", move*100, sigma*100)
+	}
+
+	return ""
+}
+
+func stdDev(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+
+	var variance float64
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	return math.Sqrt(variance / float64(len(values)))
+}
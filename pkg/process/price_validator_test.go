@@ -0,0 +1,51 @@
+package process_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/erwanlbp/trading-bot/pkg/config/configfile"
+	"github.com/erwanlbp/trading-bot/pkg/model"
+	"github.com/erwanlbp/trading-bot/pkg/process"
+)
+
+func TestCheckPrices(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	conf := configfile.PriceGuard{
+		MaxAge:   2 * time.Minute,
+		Window:   30 * time.Minute,
+		MaxSigma: decimal.NewFromInt(5),
+		MinMove:  decimal.NewFromInt(2),
+	}
+
+	// Coins moving around 0.1% each minute
+	var history []model.CoinPrice
+	for i := 10; i > 0; i-- {
+		move := decimal.NewFromFloat(1 + 0.001*float64(i%2))
+		for _, coin := range []string{"AAA", "BBB", "CCC"} {
+			history = append(history, model.CoinPrice{Coin: coin, Price: decimal.NewFromInt(100).Mul(move), Timestamp: now.Add(-time.Duration(i) * time.Minute)})
+		}
+	}
+
+	price := func(coin string, p float64) model.CoinPrice {
+		return model.CoinPrice{Coin: coin, Price: decimal.NewFromFloat(p), Timestamp: now}
+	}
+
+	check := process.CheckPrices([]model.CoinPrice{
+		price("AAA", 100.1),
+		price("BBB", 130),
+		{Coin: "CCC", Price: decimal.NewFromInt(100), Timestamp: now.Add(-5 * time.Minute)},
+	}, history, []string{"AAA", "BBB", "CCC", "DDD"}, now, conf)
+
+	assert.Equal(t, []model.CoinPrice{price("AAA", 100.1)}, check.Valid)
+	assert.Len(t, check.All, 3, "anomalies are kept to save the pairs history")
+	assert.False(t, check.HasAnomaly("AAA"))
+	assert.True(t, check.HasAnomaly("BBB"), "spike")
+	assert.True(t, check.HasAnomaly("CCC"), "stale")
+	assert.True(t, check.HasAnomaly("DDD"), "missing")
+}
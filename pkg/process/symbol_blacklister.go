@@ -30,16 +30,15 @@ func NewSymbolBlacklister(l *log.Logger, e *eventbus.Bus, r *repository.Reposito
 }
 
 func (p *SymbolBlacklister) Start(ctx context.Context) {
-	sub := p.EventBus.Subscribe(eventbus.EventFoundUnexistingSymbol)
+	sub := eventbus.Subscribe(p.EventBus, eventbus.FoundUnexistingSymbol)
 
 	go sub.Handler(ctx, p.BlacklistSymbol)
 }
 
-func (p *SymbolBlacklister) BlacklistSymbol(ctx context.Context, e eventbus.Event) {
+func (p *SymbolBlacklister) BlacklistSymbol(ctx context.Context, symbol string) {
 	p.mtx.Lock()
 	defer p.mtx.Unlock()
 
-	symbol := e.Payload.(string)
 	if err := p.Repository.BlacklistSymbol(symbol); err != nil {
 		p.Logger.Warn(fmt.Sprintf("Failed to blacklist symbol '%s', will do next tick", symbol), zap.Error(err))
 		return
@@ -0,0 +1,180 @@
+package process
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+
+	"github.com/erwanlbp/trading-bot/pkg/binance"
+	"github.com/erwanlbp/trading-bot/pkg/config/configfile"
+	"github.com/erwanlbp/trading-bot/pkg/eventbus"
+	"github.com/erwanlbp/trading-bot/pkg/log"
+	"github.com/erwanlbp/trading-bot/pkg/model"
+	"github.com/erwanlbp/trading-bot/pkg/repository"
+	"github.com/erwanlbp/trading-bot/pkg/util"
+)
+
+// Sells (a part of) the current coin to the bridge when the position value grew enough
+type TakeProfiter struct {
+	Logger     *log.Logger
+	Binance    *binance.Client
+	Repository *repository.Repository
+	EventBus   *eventbus.Bus
+	ConfigFile *configfile.ConfigFile
+}
+
+func NewTakeProfiter(l *log.Logger,
+	r *repository.Repository,
+	eb *eventbus.Bus,
+	cf *configfile.ConfigFile,
+	bc *binance.Client) *TakeProfiter {
+	return &TakeProfiter{
+		Logger:     l,
+		Repository: r,
+		EventBus:   eb,
+		ConfigFile: cf,
+		Binance:    bc,
+	}
+}
+
+func (p *TakeProfiter) Start(ctx context.Context) {
+
+	sub := eventbus.SubscribeWith(p.EventBus, eventbus.LatestOnly, CoinsPricesValidated)
+
+	go sub.Handler(ctx, p.CheckTakeProfit)
+}
+
+func (p *TakeProfiter) CheckTakeProfit(ctx context.Context, check PriceCheck) {
+	logger := p.Logger.With(zap.String("process", "take_profiter"))
+
+	conf := p.ConfigFile.TakeProfit
+	if !conf.Enabled || p.Binance.IsTradeInProgress() {
+		return
+	}
+	if paused, err := p.Repository.IsPaused(); err != nil || paused {
+		return
+	}
+
+	currentCoin, hasEverJumped, err := p.Repository.GetCurrentCoin()
+	if err != nil {
+		logger.Error("Failed getting current coin", zap.Error(err))
+		return
+	}
+	if !hasEverJumped || currentCoin.Coin == p.ConfigFile.Bridge {
+		return
+	}
+	// A spike could look like a profit
+	if check.HasAnomaly(currentCoin.Coin) {
+		return
+	}
+
+	quantity, value, err := p.PositionValue(ctx, currentCoin.Coin)
+	if err != nil {
+		logger.Error("Failed to get position value", zap.Error(err))
+		return
+	}
+
+	reference, err := p.Repository.GetPositionReference()
+	if err != nil {
+		logger.Error("Failed to get position reference", zap.Error(err))
+		return
+	}
+	// Happens if the bot was started before take profit existed, we start measuring from now
+	if reference.Timestamp.IsZero() {
+		if err := p.Repository.SetPositionReference(value, time.Now().UTC()); err != nil {
+			logger.Error("Failed to init position reference", zap.Error(err))
+			return
+		}
+		logger.Info(fmt.Sprintf("No take profit reference yet, starting from current value %s %s", value.StringFixed(2), p.ConfigFile.Bridge))
+		return
+	}
+
+	target := reference.Value.Mul(decimal.NewFromInt(1).Add(conf.Gain.Div(decimal.NewFromInt(100))))
+	if value.LessThan(target) {
+		return
+	}
+
+	logger.Info(fmt.Sprintf("Position value %s %s reached take profit target %s", value.StringFixed(2), p.ConfigFile.Bridge, target.StringFixed(2)), zap.String("reference", reference.Value.String()), zap.Time("reference_date", reference.Timestamp))
+
+	if err := p.TakeProfit(ctx, currentCoin.Coin, quantity, value, reference); err != nil {
+		logger.Error("Failed to take profit", zap.Error(err))
+	}
+
+	eventbus.Publish(p.EventBus, eventbus.SaveBalance, eventbus.NoPayload{})
+}
+
+// Return the coin quantity and its value in bridge, based on the bid
+func (p *TakeProfiter) PositionValue(ctx context.Context, coin string) (decimal.Decimal, decimal.Decimal, error) {
+	balances, err := p.Binance.GetBalance(ctx, coin)
+	if err != nil {
+		return decimal.Zero, decimal.Zero, fmt.Errorf("failed to get balance: %w", err)
+	}
+
+	lastPrices, err := p.Repository.GetCoinsLastPrice(p.ConfigFile.Bridge)
+	if err != nil {
+		return decimal.Zero, decimal.Zero, fmt.Errorf("failed to get last prices: %w", err)
+	}
+	for _, price := range lastPrices {
+		if price.Coin == coin {
+			return balances[coin], balances[coin].Mul(price.SellPrice()), nil
+		}
+	}
+
+	return decimal.Zero, decimal.Zero, fmt.Errorf("no price found for %s", coin)
+}
+
+func (p *TakeProfiter) TakeProfit(ctx context.Context, coin string, quantity, value decimal.Decimal, reference model.PositionReference) error {
+	release, err := p.Binance.TradeLock()
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	ratio := decimal.Min(p.ConfigFile.TakeProfit.SellRatio.Div(decimal.NewFromInt(100)), decimal.NewFromInt(1))
+	exit := ratio.Equal(decimal.NewFromInt(1))
+
+	sell, err := p.Binance.SellPart(ctx, coin, p.ConfigFile.Bridge, ratio)
+	if err != nil && !sell.IsPartiallyExecuted() {
+		return fmt.Errorf("failed to sell %s: %w", util.LogSymbol(coin, p.ConfigFile.Bridge), err)
+	}
+
+	proceeds := sell.Quantity().Mul(sell.AvgPrice())
+	soldShare := decimal.Zero
+	if !quantity.IsZero() {
+		soldShare = sell.Quantity().Div(quantity)
+	}
+	// Only a full sell makes the bridge our current coin
+	exit = exit && err == nil
+
+	now := time.Now().UTC()
+	takeProfit := model.TakeProfit{
+		Timestamp:      now,
+		Coin:           coin,
+		ReferenceValue: reference.Value,
+		Value:          value,
+		SoldQuantity:   sell.Quantity(),
+		SoldPrice:      sell.AvgPrice(),
+		Proceeds:       proceeds,
+		RealizedProfit: proceeds.Sub(reference.Value.Mul(soldShare)),
+		Banked:         !exit,
+	}
+	if err := repository.SimpleUpsert(p.Repository.DB.DB, takeProfit); err != nil {
+		return fmt.Errorf("failed to save take profit: %w", err)
+	}
+
+	if exit {
+		// Next jump finder tick will re-enter on a good coin, and set the new reference
+		if _, err := p.Repository.SetCurrentCoin(p.ConfigFile.Bridge, sell.Time()); err != nil {
+			return fmt.Errorf("failed to set current coin to bridge: %w", err)
+		}
+	} else if err := p.Repository.SetPositionReference(value.Sub(proceeds), now); err != nil {
+		return fmt.Errorf("failed to update position reference: %w", err)
+	}
+
+	p.Logger.Info(fmt.Sprintf("💰 Took profit selling %s %s for %s %s", takeProfit.SoldQuantity, coin, proceeds.StringFixed(2), p.ConfigFile.Bridge), zap.String("realized_profit", takeProfit.RealizedProfit.String()), zap.Bool("banked", takeProfit.Banked))
+
+	return nil
+}
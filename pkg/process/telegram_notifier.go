@@ -2,7 +2,6 @@ package process
 
 import (
 	"context"
-	"github.com/erwanlbp/trading-bot/pkg/util"
 
 	"github.com/erwanlbp/trading-bot/pkg/eventbus"
 	"github.com/erwanlbp/trading-bot/pkg/log"
@@ -24,11 +23,11 @@ func NewTelegramNotifier(l *log.Logger, e *eventbus.Bus, c *telegram.Client) *Te
 }
 
 func (n TelegramNotifier) Start(ctx context.Context) {
-	sub := n.EventBus.Subscribe(eventbus.SendNotification)
+	sub := eventbus.SubscribeWith(n.EventBus, eventbus.SubscriptionOptions{BufferSize: 256, Policy: eventbus.Block}, eventbus.SendNotification)
 
 	go sub.Handler(ctx, n.SendNotification)
 }
 
-func (n TelegramNotifier) SendNotification(ctx context.Context, e eventbus.Event) {
-	n.TelegramClient.Send(util.ToJSON(e.Payload))
+func (n TelegramNotifier) SendNotification(ctx context.Context, message string) {
+	n.TelegramClient.Send(message)
 }
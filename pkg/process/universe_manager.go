@@ -0,0 +1,112 @@
+package process
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prprprus/scheduler"
+	"go.uber.org/zap"
+
+	"github.com/erwanlbp/trading-bot/pkg/config/configfile"
+	"github.com/erwanlbp/trading-bot/pkg/config/globalconf"
+	"github.com/erwanlbp/trading-bot/pkg/log"
+	"github.com/erwanlbp/trading-bot/pkg/model"
+	"github.com/erwanlbp/trading-bot/pkg/repository"
+	"github.com/erwanlbp/trading-bot/pkg/service"
+)
+
+// Keep the coin list on the best ranked coins quoted in the bridge
+type UniverseManager struct {
+	Logger     *log.Logger
+	Repository *repository.Repository
+	ConfigFile *configfile.ConfigFile
+	Service    *service.Service
+	GlobalConf globalconf.GlobalConfModifier
+
+	// Last proposed coin list, to not propose the same changes every hour
+	lastProposal string
+	mtx          sync.Mutex
+}
+
+func NewUniverseManager(l *log.Logger, r *repository.Repository, cf *configfile.ConfigFile, s *service.Service, gc globalconf.GlobalConfModifier) *UniverseManager {
+	return &UniverseManager{
+		Logger:     l,
+		Repository: r,
+		ConfigFile: cf,
+		Service:    s,
+		GlobalConf: gc,
+	}
+}
+
+func (p *UniverseManager) Start(ctx context.Context) {
+	go func() {
+
+		Scheduler, _ := scheduler.NewScheduler(1000)
+
+		id := Scheduler.Every().Minute(5).Second(0).Do(p.UpdateUniverse, ctx)
+
+		// If ctx is canceled, we'll stop the job
+		<-ctx.Done()
+
+		if err := Scheduler.CancelJob(id); err != nil {
+			p.Logger.Error("failed canceling job", zap.Error(err))
+		}
+	}()
+}
+
+func (p *UniverseManager) UpdateUniverse(ctx context.Context) {
+	if !p.ConfigFile.Universe.Enabled {
+		return
+	}
+
+	logger := p.Logger.With(zap.String("process", "universe_manager"))
+
+	proposal, err := p.Service.ProposeUniverse(ctx)
+	if err != nil {
+		logger.Error("Failed to rank coins", zap.Error(err))
+		return
+	}
+	if !proposal.HasChanges() {
+		logger.Debug("Coin list is up to date", zap.Int("postponed", proposal.Postponed))
+		return
+	}
+
+	if !p.ConfigFile.Universe.Apply {
+		p.mtx.Lock()
+		defer p.mtx.Unlock()
+
+		coins := strings.Join(proposal.Coins, " ")
+		if coins == p.lastProposal {
+			return
+		}
+		p.lastProposal = coins
+
+		p.Logger.Info(strings.Join(append(proposal.Describe(),
+			"Send this command to apply it, then reload the config:",
+			fmt.Sprintf("`/edit_coins %s`", coins),
+		), "\n"))
+		return
+	}
+
+	if err := p.GlobalConf.ApplyCoins(ctx, proposal.Coins); err != nil {
+		logger.Error("Failed to apply the new coin list", zap.Error(err))
+		return
+	}
+
+	now := time.Now().UTC()
+	var changes []model.UniverseChange
+	for _, coin := range proposal.Added {
+		changes = append(changes, model.UniverseChange{Timestamp: now, Coin: coin, Added: true})
+	}
+	for _, coin := range proposal.Removed {
+		changes = append(changes, model.UniverseChange{Timestamp: now, Coin: coin, Added: false})
+	}
+	if err := p.Repository.SaveUniverseChanges(changes...); err != nil {
+		logger.Error("Failed to save coin list changes, the daily limit won't count them", zap.Error(err))
+	}
+
+	p.Logger.Info(strings.Join(append(proposal.Describe(), "Applied ✅"), "\n"))
+}
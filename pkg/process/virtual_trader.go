@@ -0,0 +1,312 @@
+package process
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+
+	"github.com/erwanlbp/trading-bot/pkg/binance"
+	"github.com/erwanlbp/trading-bot/pkg/config/configfile"
+	"github.com/erwanlbp/trading-bot/pkg/eventbus"
+	"github.com/erwanlbp/trading-bot/pkg/log"
+	"github.com/erwanlbp/trading-bot/pkg/model"
+	"github.com/erwanlbp/trading-bot/pkg/repository"
+	"github.com/erwanlbp/trading-bot/pkg/util"
+)
+
+// Runs the jump decision of the live jump finder on validated prices for virtual strategies, and records virtual jumps instead of trading
+type VirtualTrader struct {
+	Logger     *log.Logger
+	Binance    *binance.Client
+	Repository *repository.Repository
+	EventBus   *eventbus.Bus
+	ConfigFile *configfile.ConfigFile
+	JumpFinder *JumpFinder
+}
+
+func NewVirtualTrader(l *log.Logger,
+	r *repository.Repository,
+	eb *eventbus.Bus,
+	cf *configfile.ConfigFile,
+	bc *binance.Client,
+	jf *JumpFinder) *VirtualTrader {
+	return &VirtualTrader{
+		Logger:     l,
+		Repository: r,
+		EventBus:   eb,
+		ConfigFile: cf,
+		Binance:    bc,
+		JumpFinder: jf,
+	}
+}
+
+func (p *VirtualTrader) Start(ctx context.Context) {
+
+	sub := eventbus.SubscribeWith(p.EventBus, eventbus.LatestOnly, CoinsPricesValidated)
+
+	go sub.Handler(ctx, p.RunStrategies)
+}
+
+func (p *VirtualTrader) RunStrategies(ctx context.Context, check PriceCheck) {
+	strategies := p.ConfigFile.VirtualStrategies()
+	if len(strategies) == 0 || len(check.All) == 0 {
+		return
+	}
+
+	// All strategies run on the same prices
+	for _, strategy := range strategies {
+		if err := p.RunStrategy(ctx, strategy, check); err != nil {
+			p.Logger.Error(fmt.Sprintf("Failed to run virtual strategy '%s'", strategy.Name), zap.Error(err))
+		}
+	}
+}
+
+// Same decision as the jump finder: trusted and liquid coins only, trading windows, paths up to max_hops, and the expected slippage removed from the diff
+func (p *VirtualTrader) RunStrategy(ctx context.Context, strategy configfile.VirtualStrategy, check PriceCheck) error {
+	name := strategy.Name
+	logger := p.Logger.With(zap.String("process", "virtual_trader"), zap.String("strategy", name))
+
+	coins := p.ConfigFile.StrategyCoins(strategy)
+	jumpConf := p.ConfigFile.StrategyJump(strategy)
+	prices := util.AsMap(check.All, func(c model.CoinPrice) string { return c.Coin })
+
+	portfolio, exists, err := p.Repository.GetVirtualPortfolio(name)
+	if err != nil {
+		return fmt.Errorf("failed to get virtual portfolio: %w", err)
+	}
+	if !exists {
+		return p.InitPortfolio(ctx, strategy, coins, prices)
+	}
+
+	if _, ok := prices[portfolio.Coin]; !ok {
+		return fmt.Errorf("no price found for current coin %s", portfolio.Coin)
+	}
+
+	pairsRatio, err := p.CalculateRatios(ctx, name, coins, check)
+	if err != nil {
+		return err
+	}
+
+	now := check.Time()
+	wantedGain := decimal.NewFromInt(1).Add(jumpConf.GetNeededGain(portfolio.LastJump))
+
+	// Pairs are not logged, the live jump finder already does
+	jumpsFrom, diffs := p.JumpFinder.JumpCandidates(ctx, logger, pairsRatio, "", wantedGain)
+
+	var computedDiff []model.VirtualDiff
+	for _, diff := range diffs {
+		if diff.FromCoin != portfolio.Coin {
+			continue
+		}
+		computedDiff = append(computedDiff, model.VirtualDiff{
+			Strategy:   name,
+			FromCoin:   diff.FromCoin,
+			ToCoin:     diff.ToCoin,
+			Timestamp:  now,
+			Diff:       diff.Diff,
+			NeededDiff: diff.NeededDiff,
+		})
+	}
+	if err := p.Repository.ReplaceAllVirtualDiff(name, computedDiff); err != nil {
+		logger.Warn("Error while updating virtual diff in DB", zap.Error(err))
+	}
+
+	if !p.JumpFinder.IsTradingAllowed(logger, now) {
+		return nil
+	}
+
+	var goodPaths []JumpPath
+	for _, path := range FindJumpPaths(portfolio.Coin, jumpsFrom, jumpConf.MaxHops) {
+		if path.Diff.GreaterThanOrEqual(wantedGain) {
+			goodPaths = append(goodPaths, path)
+		}
+	}
+	sort.SliceStable(goodPaths, func(i, j int) bool {
+		return goodPaths[i].Diff.GreaterThan(goodPaths[j].Diff)
+	})
+
+	for _, path := range goodPaths {
+		estimates, slippageMultiplier, err := p.JumpFinder.EstimatePathSlippage(ctx, path, portfolio.Quantity)
+		if err != nil {
+			logger.Debug(fmt.Sprintf("Failed to estimate slippage for virtual path %s, ignoring it", path.LogSymbol()), zap.Error(err))
+			continue
+		}
+		netDiff := path.Diff.Mul(slippageMultiplier)
+		if netDiff.LessThan(wantedGain) {
+			continue
+		}
+
+		if err := p.JumpPath(name, portfolio, path, estimates, coins, prices, now); err != nil {
+			return err
+		}
+
+		logger.Info(fmt.Sprintf("👻 [%s] Virtual jump %s", name, path.LogSymbol()), zap.String("diff", path.Diff.String()), zap.String("net_diff", netDiff.String()), zap.String("threshold", wantedGain.String()))
+		return nil
+	}
+
+	return nil
+}
+
+// Ratios between the strategy coins, as the jump finder computes them from the live pairs but based on the virtual pairs.
+// Only the ratios we could jump to are returned: trusted prices and liquid to_coin
+func (p *VirtualTrader) CalculateRatios(ctx context.Context, name string, coins []string, check PriceCheck) ([]model.PairWithTickerRatio, error) {
+	pairs, err := p.Repository.GetVirtualPairs(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get virtual pairs: %w", err)
+	}
+	livePairs, err := p.Repository.GetPairs()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get live pairs: %w", err)
+	}
+
+	prices := util.AsMap(check.All, func(c model.CoinPrice) string { return c.Coin })
+	illiquidCoins := p.JumpFinder.IlliquidCoins(ctx, coins)
+	now := check.Time()
+
+	var newPairs []model.VirtualPair
+	var res []model.PairWithTickerRatio
+	for _, from := range coins {
+		for _, to := range coins {
+			fromPrice, okFrom := prices[from]
+			toPrice, okTo := prices[to]
+			if from == to || !okFrom || !okTo || !fromPrice.Price.IsPositive() || !toPrice.Price.IsPositive() {
+				continue
+			}
+			if check.HasAnomaly(from) || check.HasAnomaly(to) || illiquidCoins[to] != "" {
+				continue
+			}
+
+			executableRatio := fromPrice.SellPrice().Div(toPrice.BuyPrice())
+
+			symbol := util.Symbol(from, to)
+			pair, ok := pairs[symbol]
+			if !ok {
+				// First time we see this pair, start from the live ratio if there's one, otherwise from now
+				pair = model.VirtualPair{Strategy: name, FromCoin: from, ToCoin: to, LastJumpRatio: executableRatio, LastJumpRatioBasedOn: now}
+				if livePair, ok := livePairs[symbol]; ok && !livePair.LastJumpRatio.IsZero() {
+					pair.LastJumpRatio = livePair.LastJumpRatio
+					pair.LastJumpRatioBasedOn = livePair.LastJumpRatioBasedOn
+				}
+				newPairs = append(newPairs, pair)
+			}
+
+			res = append(res, model.PairWithTickerRatio{
+				Pair: model.Pair{
+					FromCoin:             from,
+					ToCoin:               to,
+					Exists:               true,
+					LastJumpRatio:        pair.LastJumpRatio,
+					LastJumpRatioBasedOn: pair.LastJumpRatioBasedOn,
+				},
+				Ratio:           fromPrice.Price.Div(toPrice.Price),
+				ExecutableRatio: executableRatio,
+				Timestamp:       now,
+			})
+		}
+	}
+
+	if err := repository.SimpleUpsert(p.Repository.DB.DB, newPairs...); err != nil {
+		return nil, fmt.Errorf("failed saving new virtual pairs: %w", err)
+	}
+
+	return res, nil
+}
+
+// Record a virtual jump for each hop of the path, at the prices the slippage estimate expects
+func (p *VirtualTrader) JumpPath(name string, portfolio model.VirtualPortfolio, path JumpPath, estimates []binance.SlippageEstimate, coins []string, prices map[string]model.CoinPrice, now time.Time) error {
+	for i, hop := range path.Hops {
+		estimate := estimates[i]
+		jump := model.VirtualJump{
+			Strategy:     name,
+			FromCoin:     hop.Pair.Pair.FromCoin,
+			ToCoin:       hop.Pair.Pair.ToCoin,
+			Timestamp:    now,
+			FromQuantity: portfolio.Quantity,
+			FromPrice:    estimate.SellVWAP,
+			ToQuantity:   portfolio.Quantity.Mul(estimate.SellVWAP).Div(estimate.BuyVWAP).Mul(hop.Fee),
+			ToPrice:      estimate.BuyVWAP,
+		}
+
+		portfolio.Coin = jump.ToCoin
+		portfolio.Quantity = jump.ToQuantity
+		portfolio.LastJump = now
+
+		// Same as a real jump, every pair to the new coin is based on the jump prices
+		var pairsToSave []model.VirtualPair
+		for _, coin := range coins {
+			price, ok := prices[coin]
+			if coin == jump.ToCoin || !ok {
+				continue
+			}
+			fromPrice := price.SellPrice()
+			if coin == jump.FromCoin {
+				fromPrice = jump.FromPrice
+			}
+			pairsToSave = append(pairsToSave, model.VirtualPair{
+				Strategy:             name,
+				FromCoin:             coin,
+				ToCoin:               jump.ToCoin,
+				LastJumpRatio:        fromPrice.Div(jump.ToPrice),
+				LastJumpRatioBasedOn: now,
+			})
+		}
+
+		if err := p.Repository.SaveVirtualJump(jump, portfolio, pairsToSave); err != nil {
+			return fmt.Errorf("failed to save virtual jump %s: %w", hop.Pair.Pair.LogSymbol(), err)
+		}
+	}
+	return nil
+}
+
+// Virtual portfolio starts on the live current coin if the strategy has it, or the start coin, or the first coin
+func (p *VirtualTrader) InitPortfolio(ctx context.Context, strategy configfile.VirtualStrategy, coins []string, prices map[string]model.CoinPrice) error {
+	name := strategy.Name
+	if len(coins) == 0 {
+		return fmt.Errorf("no coins for strategy")
+	}
+
+	hasCoin := func(coin string) bool {
+		return util.Exists(coins, func(c string) bool { return c == coin })
+	}
+
+	sortedCoins := append([]string{}, coins...)
+	sort.Strings(sortedCoins)
+	startCoin := sortedCoins[0]
+	if p.ConfigFile.StartCoin != nil && hasCoin(*p.ConfigFile.StartCoin) {
+		startCoin = *p.ConfigFile.StartCoin
+	}
+	if cc, _, err := p.Repository.GetCurrentCoin(); err == nil && hasCoin(cc.Coin) {
+		startCoin = cc.Coin
+	}
+
+	price, ok := prices[startCoin]
+	if !ok {
+		return fmt.Errorf("no price found for start coin %s", startCoin)
+	}
+
+	feeMultiplier := decimal.NewFromInt(1)
+	if fee, err := p.Binance.GetFee(ctx, util.Symbol(startCoin, p.ConfigFile.Bridge)); err == nil {
+		feeMultiplier = feeMultiplier.Sub(fee)
+	}
+
+	now := time.Now().UTC()
+	portfolio := model.VirtualPortfolio{
+		Strategy:     name,
+		Coin:         startCoin,
+		Quantity:     strategy.StartBalance.Mul(feeMultiplier).Div(price.BuyPrice()),
+		LastJump:     now,
+		StartBalance: strategy.StartBalance,
+		StartedOn:    now,
+	}
+	if err := repository.SimpleUpsert(p.Repository.DB.DB, portfolio); err != nil {
+		return fmt.Errorf("failed saving virtual portfolio: %w", err)
+	}
+
+	p.Logger.Info(fmt.Sprintf("👻 [%s] Started virtual portfolio with %s %s", name, portfolio.Quantity.StringFixed(4), startCoin))
+
+	return nil
+}
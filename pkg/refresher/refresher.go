@@ -49,8 +49,20 @@ func NewRefresher[T any](logger *log.Logger, tickerDuration time.Duration, handl
 	return &b
 }
 
-func (r *Refresher[T]) Data(ctx context.Context) T {
+// Start refreshing in background until the context is done, must be given the app context, not a request one
+func (r *Refresher[T]) Start(ctx context.Context) {
 	r.initOnce.Do(func() { r.start(ctx) })
+}
+
+// Data is loaded with the given context if it never was, the refreshes are done by Start
+func (r *Refresher[T]) Data(ctx context.Context) T {
+	r.mtx.RLock()
+	loaded := !r.lastSuccessful.IsZero()
+	r.mtx.RUnlock()
+
+	if !loaded {
+		r.triggerRefresh(ctx)
+	}
 
 	r.mtx.RLock()
 	defer r.mtx.RUnlock()
@@ -69,18 +81,19 @@ func (r *Refresher[T]) Stop() {
 }
 
 func (r *Refresher[T]) start(ctx context.Context) {
-	// Trigger at startup to be sure it's loaded
-	r.triggerRefresh(ctx)
+	closed := r.close
 	go func() {
+		// Trigger at startup to be sure it's loaded
+		r.triggerRefresh(ctx)
 		for {
 			select {
 			case <-r.ticker.C:
 				r.triggerRefresh(ctx)
 			case <-ctx.Done():
 				r.Stop()
-				break
-			case <-r.close:
-				break
+				return
+			case <-closed:
+				return
 			}
 		}
 	}()
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+
+	"github.com/erwanlbp/trading-bot/pkg/archive"
+	"github.com/erwanlbp/trading-bot/pkg/model"
+)
+
+// Archive of the old history. It's read even if archiving is disabled, files may be there from before
+func (r *Repository) Archive() *archive.Archive {
+	if r.ConfigFile == nil {
+		return nil
+	}
+	return archive.New(r.ConfigFile.Archive.Path())
+}
+
+// Move rows of the history tables older than the date to the archive, month by month. Returns the number of archived rows per table
+func (r *Repository) ArchiveHistory(before time.Time) (map[string]int64, error) {
+	res := make(map[string]int64)
+	var err error
+	if res[model.CoinPriceTableName], err = archiveTable(r, before, func(p model.CoinPrice) time.Time { return p.Timestamp }); err != nil {
+		return res, fmt.Errorf("failed archiving %s: %w", model.CoinPriceTableName, err)
+	}
+	if res[model.PairHistoryTableName], err = archiveTable(r, before, func(p model.PairHistory) time.Time { return p.Timestamp }); err != nil {
+		return res, fmt.Errorf("failed archiving %s: %w", model.PairHistoryTableName, err)
+	}
+	if res[model.JumpTableName], err = archiveTable(r, before, func(j model.Jump) time.Time { return j.Timestamp }); err != nil {
+		return res, fmt.Errorf("failed archiving %s: %w", model.JumpTableName, err)
+	}
+	if res[model.BalanceHistoryTableName], err = archiveTable(r, before, func(b model.BalanceHistory) time.Time { return b.Timestamp }); err != nil {
+		return res, fmt.Errorf("failed archiving %s: %w", model.BalanceHistoryTableName, err)
+	}
+	return res, nil
+}
+
+func archiveTable[T schema.Tabler](r *Repository, before time.Time, timestamp func(T) time.Time) (int64, error) {
+	a := r.Archive()
+	if a == nil {
+		return 0, nil
+	}
+
+	var first []T
+	if err := r.DB.Order("timestamp").Limit(1).Find(&first).Error; err != nil {
+		return 0, err
+	}
+	if len(first) == 0 {
+		return 0, nil
+	}
+
+	var total int64
+	for month := archive.MonthOf(timestamp(first[0])); month.Before(before); month = month.AddDate(0, 1, 0) {
+		end := month.AddDate(0, 1, 0)
+		if end.After(before) {
+			end = before
+		}
+
+		err := r.DB.Transaction(func(tx *gorm.DB) error {
+			var rows []T
+			if err := tx.Where("timestamp >= ? AND timestamp < ?", month, end).Order("timestamp").Find(&rows).Error; err != nil {
+				return err
+			}
+			if len(rows) == 0 {
+				return nil
+			}
+
+			pending, err := archive.Prepare(a, tx, month, rows)
+			if err != nil {
+				return fmt.Errorf("failed writing partition %s: %w", month.Format("2006-01"), err)
+			}
+			var zero T
+			if err := tx.Where("timestamp >= ? AND timestamp < ?", month, end).Delete(&zero).Error; err != nil {
+				pending.Abort()
+				return err
+			}
+			// Moved before the commit: if the commit fails, rows are both in the DB and the archive rather than lost
+			if err := pending.Commit(); err != nil {
+				pending.Abort()
+				return err
+			}
+			total += int64(len(rows))
+			return nil
+		})
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// Archived partitions of the table of the model, oldest first, nil if there is no archive
+func archivePartitions[T schema.Tabler](r *Repository) ([]archive.Partition, error) {
+	a := r.Archive()
+	if a == nil {
+		return nil, nil
+	}
+	var zero T
+	res, err := a.Partitions(zero.TableName())
+	if err != nil {
+		return nil, fmt.Errorf("failed listing archive: %w", err)
+	}
+	return res, nil
+}
+
+// Archived rows since the date, nil if there is no archive
+func readArchive[T schema.Tabler](r *Repository, since time.Time) ([]T, error) {
+	a := r.Archive()
+	if a == nil {
+		return nil, nil
+	}
+	res, err := archive.Read[T](a, r.DB.DB, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading archive: %w", err)
+	}
+	return res, nil
+}
@@ -1,6 +1,11 @@
 package repository
 
-import "github.com/erwanlbp/trading-bot/pkg/model"
+import (
+	"time"
+
+	"github.com/erwanlbp/trading-bot/pkg/archive"
+	"github.com/erwanlbp/trading-bot/pkg/model"
+)
 
 func (r *Repository) GetBalanceHistory() ([]model.BalanceHistory, error) {
 	var res []model.BalanceHistory
@@ -19,3 +24,65 @@ func (r *Repository) GetLastBalanceHistory() (model.BalanceHistory, error) {
 	}
 	return res, nil
 }
+
+// Archived history is older than the DB one, it's read first, a partition at a time from the date
+func (r *Repository) GetFirstBalanceHistoryAfter(t time.Time) (model.BalanceHistory, error) {
+	partitions, err := archivePartitions[model.BalanceHistory](r)
+	if err != nil {
+		return model.BalanceHistory{}, err
+	}
+	for _, partition := range partitions {
+		if !partition.End().After(t) {
+			continue
+		}
+		archived, err := archive.ReadPartition[model.BalanceHistory](r.DB.DB, partition)
+		if err != nil {
+			return model.BalanceHistory{}, err
+		}
+		var res model.BalanceHistory
+		for _, b := range archived {
+			if !b.Timestamp.Before(t) && (res.Timestamp.IsZero() || b.Timestamp.Before(res.Timestamp)) {
+				res = b
+			}
+		}
+		if !res.Timestamp.IsZero() {
+			return res, nil
+		}
+	}
+
+	var res model.BalanceHistory
+	err = r.DB.Where("timestamp >= ?", t).Order("timestamp").Limit(1).Find(&res).Error
+	return res, err
+}
+
+func (r *Repository) GetLastBalanceHistoryBefore(t time.Time) (model.BalanceHistory, error) {
+	var res model.BalanceHistory
+	err := r.DB.Where("timestamp < ?", t).Order("timestamp desc").Limit(1).Find(&res).Error
+	if err != nil || !res.Timestamp.IsZero() {
+		return res, err
+	}
+
+	// Nothing left in the DB before the date, it may have been archived. Read a partition at a time, back from the date
+	partitions, err := archivePartitions[model.BalanceHistory](r)
+	if err != nil {
+		return res, err
+	}
+	for i := len(partitions) - 1; i >= 0; i-- {
+		if !partitions[i].Month.Before(t) {
+			continue
+		}
+		archived, err := archive.ReadPartition[model.BalanceHistory](r.DB.DB, partitions[i])
+		if err != nil {
+			return res, err
+		}
+		for _, b := range archived {
+			if b.Timestamp.Before(t) && b.Timestamp.After(res.Timestamp) {
+				res = b
+			}
+		}
+		if !res.Timestamp.IsZero() {
+			return res, nil
+		}
+	}
+	return res, nil
+}
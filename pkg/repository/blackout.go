@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/erwanlbp/trading-bot/pkg/model"
+)
+
+// Blackouts not over yet, soonest first
+func (r *Repository) GetUpcomingBlackouts(now time.Time) ([]model.Blackout, error) {
+	var res []model.Blackout
+	err := r.DB.Where("ends_at > ?", now).Order("starts_at").Find(&res).Error
+	return res, err
+}
+
+func (r *Repository) AddBlackout(blackout *model.Blackout) error {
+	return r.DB.Create(blackout).Error
+}
+
+func (r *Repository) DeleteBlackout(id uint) (bool, error) {
+	res := r.DB.Where("id = ?", id).Delete(&model.Blackout{})
+	return res.RowsAffected > 0, res.Error
+}
+
+// Blackout going on at the date, if any
+func (r *Repository) GetCurrentBlackout(now time.Time) (model.Blackout, bool, error) {
+	var res []model.Blackout
+	err := r.DB.Where("starts_at <= ? AND ends_at > ?", now, now).Order("ends_at desc").Limit(1).Find(&res).Error
+	if err != nil || len(res) == 0 {
+		return model.Blackout{}, false, err
+	}
+	return res[0], true, nil
+}
+
+// How far we look for the next trading time
+const tradingTimeHorizon = 15 * 24 * time.Hour
+
+// Return now if jumps are allowed now, otherwise when the next trading window opens, outside of blackouts.
+//
+// Scans the next minutes, it's meant to be displayed, not checked on every tick
+func (r *Repository) NextTradingTime(now time.Time) (time.Time, error) {
+	blackouts, err := r.GetUpcomingBlackouts(now)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	allowed := func(t time.Time) bool {
+		if !r.ConfigFile.TradingWindows.IsOpen(t) {
+			return false
+		}
+		for _, blackout := range blackouts {
+			if blackout.Contains(t) {
+				return false
+			}
+		}
+		return true
+	}
+
+	if allowed(now) {
+		return now, nil
+	}
+	for t := now.Truncate(time.Minute).Add(time.Minute); t.Before(now.Add(tradingTimeHorizon)); t = t.Add(time.Minute) {
+		if allowed(t) {
+			return t, nil
+		}
+	}
+	return time.Time{}, errors.New("no trading window in the next 15 days")
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+
+	"github.com/erwanlbp/trading-bot/pkg/constant"
+	"github.com/erwanlbp/trading-bot/pkg/model"
+)
+
+// Return a non tripped circuit breaker if there's none yet
+func (r *Repository) GetCircuitBreaker() (model.CircuitBreaker, error) {
+	var res model.CircuitBreaker
+	err := r.DB.Limit(1).Find(&res).Error
+	res.ID = 1
+	return res, err
+}
+
+func (r *Repository) TripCircuitBreaker(reason string, ts time.Time) error {
+	cb, err := r.GetCircuitBreaker()
+	if err != nil {
+		return err
+	}
+	cb.Tripped = true
+	cb.Reason = reason
+	cb.TrippedOn = ts
+	return SimpleUpsert(r.DB.DB, cb)
+}
+
+func (r *Repository) ResumeCircuitBreaker(ts time.Time) error {
+	return SimpleUpsert(r.DB.DB, model.CircuitBreaker{ID: 1, ResumedOn: ts})
+}
+
+// Increment the consecutive failed jumps count and return it
+func (r *Repository) AddFailedJump() (int, error) {
+	var cb model.CircuitBreaker
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Limit(1).Find(&cb).Error; err != nil {
+			return err
+		}
+		cb.ID = 1
+		cb.ConsecutiveFailedJumps += 1
+		return SimpleUpsert(tx, cb)
+	})
+	return cb.ConsecutiveFailedJumps, err
+}
+
+func (r *Repository) ResetFailedJumps() error {
+	return r.DB.Model(&model.CircuitBreaker{}).Where("id = ?", 1).Update("consecutive_failed_jumps", 0).Error
+}
+
+// Highest balance saved since the date, valued in the coin
+func (r *Repository) GetPeakBalanceSince(t time.Time, coin string) (decimal.Decimal, error) {
+	query := r.DB.Table(model.BalanceHistoryTableName).Where("timestamp >= ?", t)
+	switch coin {
+	case constant.USDT:
+		query = query.Select("COALESCE(MAX(usdt_balance), 0)")
+	case constant.BTC:
+		query = query.Select("COALESCE(MAX(btc_balance), 0)")
+	default:
+		query = query.Select("COALESCE(MAX(bridge_balance), 0)").Where("bridge = ?", coin)
+	}
+	var res decimal.Decimal
+	err := query.Find(&res).Error
+	return res, err
+}
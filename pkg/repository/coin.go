@@ -8,10 +8,15 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/erwanlbp/trading-bot/pkg/model"
+	"github.com/erwanlbp/trading-bot/pkg/util"
 )
 
 func (r *Repository) DisableCoin(coin string) error {
-	return r.DB.DB.Table(model.CoinTableName).Where("coin = ?", coin).Update("enabled", 0).Error
+	return r.DB.DB.Table(model.CoinTableName).Where("coin = ?", coin).Update("enabled", false).Error
+}
+
+func (r *Repository) EnableCoin(coin string) error {
+	return r.DB.DB.Table(model.CoinTableName).Where("coin = ?", coin).Update("enabled", true).Error
 }
 
 func (r *Repository) GetCurrentCoin() (model.CurrentCoin, bool, error) {
@@ -85,26 +90,54 @@ func (r *Repository) DeleteAllCoins(tx *gorm.DB) error {
 	return tx.Exec("DELETE FROM " + model.CoinTableName).Error
 }
 
-func (r *Repository) CleanCoinPriceHistory() (inserted int64, deleted int64, err error) {
-	if err := r.DB.Transaction(func(tx *gorm.DB) error {
-		resInsert := r.DB.DB.Exec(`
-		INSERT OR REPLACE INTO ` + model.CoinPriceTableName + ` (coin, alt_coin, timestamp, price, averaged)
-		SELECT coin, alt_coin, strftime('%Y-%m-%d %H:00:00',timestamp) AS "timestamp" , avg(price) AS price , 1 FROM ` + model.CoinPriceTableName + ` cph WHERE (cph.averaged IS NULL OR cph.averaged = 0) AND timestamp < strftime('%Y-%m-%d 00:00:00','now')
-		GROUP BY 1,2,3`)
-		if resInsert.Error != nil {
-			return fmt.Errorf("failed to insert aggregated data: %w", err)
-		}
-		inserted = resInsert.RowsAffected
+// Aggregate the prices older than the date into buckets of the resolution, coin by coin. Finer rows are replaced by the buckets
+func (r *Repository) DownsampleCoinPrices(resolution time.Duration, before time.Time) (inserted int64, deleted int64, err error) {
+	var symbols []struct {
+		Coin    string
+		AltCoin string
+	}
+	if err := r.DB.Table(model.CoinPriceTableName).Distinct("coin", "alt_coin").Where("resolution < ? AND timestamp < ?", resolution, before).Find(&symbols).Error; err != nil {
+		return 0, 0, fmt.Errorf("failed to get coins to downsample: %w", err)
+	}
 
-		resDelete := r.DB.DB.Exec(`DELETE FROM ` + model.CoinPriceTableName + ` WHERE (averaged IS NULL OR averaged = 0) AND timestamp < strftime('%Y-%m-%d 00:00:00','now')`)
-		if resDelete.Error != nil {
-			return fmt.Errorf("failed to delete old data after aggregation: %w", err)
+	for _, symbol := range symbols {
+		if err := r.DB.Transaction(func(tx *gorm.DB) error {
+			var rows []model.CoinPrice
+			if err := tx.Where("coin = ? AND alt_coin = ? AND resolution < ? AND timestamp < ?", symbol.Coin, symbol.AltCoin, resolution, before).Order("timestamp").Find(&rows).Error; err != nil {
+				return fmt.Errorf("failed to get rows to downsample: %w", err)
+			}
+			if len(rows) == 0 {
+				return nil
+			}
+			from := rows[0].Timestamp.Truncate(resolution)
+
+			// Buckets already done are merged, a late row could be in one
+			var buckets []model.CoinPrice
+			if err := tx.Where("coin = ? AND alt_coin = ? AND resolution = ? AND timestamp >= ? AND timestamp < ?", symbol.Coin, symbol.AltCoin, resolution, from, before).Find(&buckets).Error; err != nil {
+				return fmt.Errorf("failed to get existing buckets: %w", err)
+			}
+
+			resDelete := tx.Where("coin = ? AND alt_coin = ? AND resolution < ? AND timestamp < ?", symbol.Coin, symbol.AltCoin, resolution, before).Delete(&model.CoinPrice{})
+			if resDelete.Error != nil {
+				return fmt.Errorf("failed to delete rows after aggregation: %w", resDelete.Error)
+			}
+			resDeleteBuckets := tx.Where("coin = ? AND alt_coin = ? AND resolution = ? AND timestamp >= ? AND timestamp < ?", symbol.Coin, symbol.AltCoin, resolution, from, before).Delete(&model.CoinPrice{})
+			if resDeleteBuckets.Error != nil {
+				return fmt.Errorf("failed to delete existing buckets: %w", resDeleteBuckets.Error)
+			}
+
+			newBuckets := model.DownsampleCoinPrices(append(rows, buckets...), resolution)
+			if err := tx.CreateInBatches(newBuckets, 500).Error; err != nil {
+				return fmt.Errorf("failed to insert buckets: %w", err)
+			}
+
+			inserted += int64(len(newBuckets)) - resDeleteBuckets.RowsAffected
+			deleted += resDelete.RowsAffected
+			return nil
+		}); err != nil {
+			return inserted, deleted, fmt.Errorf("%s: %w", util.Symbol(symbol.Coin, symbol.AltCoin), err)
 		}
-		deleted = resDelete.RowsAffected
-
-		return nil
-	}); err != nil {
-		return 0, 0, err
 	}
-	return
+
+	return inserted, deleted, nil
 }
@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/erwanlbp/trading-bot/pkg/model"
+)
+
+func (r *Repository) GetCoinSuspensions() (map[string]model.CoinSuspension, error) {
+	var res []model.CoinSuspension
+	err := r.DB.Find(&res).Error
+	suspensions := make(map[string]model.CoinSuspension)
+	for _, s := range res {
+		suspensions[s.Coin] = s
+	}
+	return suspensions, err
+}
+
+func (r *Repository) SuspendCoin(coin, reason string, now, until time.Time) error {
+	if err := r.DisableCoin(coin); err != nil {
+		return err
+	}
+	return SimpleUpsert(r.DB.DB, model.CoinSuspension{Coin: coin, Active: true, SuspendedOn: now, Until: until, Reason: reason})
+}
+
+// End the suspension, the coin is enabled again only if asked (it can have been removed from the config meanwhile)
+func (r *Repository) EndCoinSuspension(coin string, enable bool) error {
+	if enable {
+		if err := r.EnableCoin(coin); err != nil {
+			return err
+		}
+	}
+	return r.DB.Model(&model.CoinSuspension{}).Where("coin = ?", coin).Update("active", false).Error
+}
@@ -2,21 +2,60 @@ package repository
 
 import (
 	"github.com/erwanlbp/trading-bot/pkg/model"
+	"github.com/erwanlbp/trading-bot/pkg/util"
 )
 
+// Archived jumps are only included with IncludeArchive, first
 func (r *Repository) GetJumps(filters ...QueryFilter) ([]model.Jump, error) {
-	var res []model.Jump
-
 	req := r.DB.DB
 
 	for _, f := range filters {
 		req = f(req)
 	}
 
-	err := req.Find(&res).Error
+	var res []model.Jump
+	if since, ok := archiveSince(req); ok {
+		archived, err := readArchive[model.Jump](r, since)
+		if err != nil {
+			return nil, err
+		}
+		res = util.FilterSlice(archived, func(j model.Jump) bool { return !j.Timestamp.Before(since) })
+	}
+
+	var live []model.Jump
+	err := req.Find(&live).Error
 	if err != nil {
 		return res, err
 	}
 
-	return res, nil
+	return append(res, live...), nil
+}
+
+// True if the last manual trade is an exit to the bridge, then we must stay on the bridge until a manual entry
+func (r *Repository) HasManuallyExited() (bool, error) {
+	var res []model.ManualTrade
+	err := r.DB.Order("timestamp desc").Limit(1).Find(&res).Error
+	if err != nil || len(res) == 0 {
+		return false, err
+	}
+	return res[0].Side == model.ManualExit, nil
+}
+
+// Last n completed holdings (at most) of each coin, oldest first. Like model.Holdings, only jumps following each other make a holding.
+//
+// Archived jumps are not read, it's checked on every tick
+func (r *Repository) GetLastHoldings(n int) (map[string][]model.Holding, error) {
+	var holdings []model.Holding
+	// The exit is joined back rather than taken with LEAD, SQLite would return its timestamp as a string
+	err := r.DB.Raw(`SELECT h.coin, h.entered_on, x.timestamp AS exited_on, h.entry_price, x.from_price AS exit_price FROM (
+		SELECT coin, entered_on, entry_price, ROW_NUMBER() OVER (PARTITION BY coin ORDER BY entered_on DESC) AS recency FROM (
+			SELECT to_coin AS coin, timestamp AS entered_on, to_price AS entry_price, LEAD(from_coin) OVER (ORDER BY timestamp) AS exit_coin FROM jumps
+		) AS consecutive WHERE exit_coin = coin AND coin <> ?
+	) AS h
+	JOIN jumps AS x ON x.from_coin = h.coin AND x.timestamp = (SELECT MIN(timestamp) FROM jumps WHERE timestamp > h.entered_on)
+	WHERE h.recency <= ? ORDER BY h.entered_on`, r.ConfigFile.Bridge, n).Scan(&holdings).Error
+	if err != nil {
+		return nil, err
+	}
+	return util.GroupByProperty(holdings, func(h model.Holding) string { return h.Coin }), nil
 }
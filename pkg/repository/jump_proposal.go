@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/erwanlbp/trading-bot/pkg/model"
+)
+
+func (r *Repository) GetJumpProposals(filters ...QueryFilter) ([]model.JumpProposal, error) {
+	var res []model.JumpProposal
+
+	req := r.DB.DB
+
+	for _, f := range filters {
+		req = f(req)
+	}
+
+	err := req.Find(&res).Error
+	return res, err
+}
+
+func (r *Repository) GetJumpProposal(id uint) (model.JumpProposal, bool, error) {
+	var res []model.JumpProposal
+	err := r.DB.Where("id = ?", id).Limit(1).Find(&res).Error
+	if err != nil || len(res) == 0 {
+		return model.JumpProposal{}, false, err
+	}
+	return res[0], true, nil
+}
+
+func (r *Repository) CreateJumpProposal(proposal *model.JumpProposal) error {
+	return r.DB.Create(proposal).Error
+}
+
+func (r *Repository) SaveJumpProposal(proposal model.JumpProposal) error {
+	return r.DB.Save(&proposal).Error
+}
+
+func (r *Repository) HasPendingJumpProposal() (bool, error) {
+	var count int64
+	err := r.DB.Model(&model.JumpProposal{}).Where("status = ?", model.JumpProposalPending).Count(&count).Error
+	return count > 0, err
+}
+
+// Mark as expired the pending proposals created before the date, return how many there were
+func (r *Repository) ExpireJumpProposals(createdBefore, now time.Time) (int64, error) {
+	res := r.DB.Model(&model.JumpProposal{}).
+		Where("status = ?", model.JumpProposalPending).
+		Where("created_on < ?", createdBefore).
+		Updates(map[string]interface{}{"status": model.JumpProposalExpired, "decided_on": now})
+	return res.RowsAffected, res.Error
+}
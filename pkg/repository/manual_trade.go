@@ -0,0 +1,9 @@
+package repository
+
+import (
+	"github.com/erwanlbp/trading-bot/pkg/model"
+)
+
+func (r *Repository) SaveManualTrade(trade model.ManualTrade) error {
+	return r.DB.Create(&trade).Error
+}
@@ -3,10 +3,22 @@ package repository
 import (
 	"time"
 
+	"github.com/erwanlbp/trading-bot/pkg/archive"
 	"github.com/erwanlbp/trading-bot/pkg/model"
 )
 
 func (r *Repository) GetBotFirstLaunch() (time.Time, error) {
+	// Archived history is older than the DB one
+	if a := r.Archive(); a != nil {
+		jumps, err := archive.ReadFirst[model.Jump](a, r.DB.DB)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if first, ok := firstTime(jumps, func(j model.Jump) time.Time { return j.Timestamp }); ok {
+			return first, nil
+		}
+	}
+
 	// We say that the first jump sets the first bot activity
 	{
 		var res model.Jump
@@ -20,6 +32,15 @@ func (r *Repository) GetBotFirstLaunch() (time.Time, error) {
 	}
 
 	// If we never jumped, we use the first coin_price we have
+	if a := r.Archive(); a != nil {
+		prices, err := archive.ReadFirst[model.CoinPrice](a, r.DB.DB)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if first, ok := firstTime(prices, func(p model.CoinPrice) time.Time { return p.Timestamp }); ok {
+			return first, nil
+		}
+	}
 	{
 		var res model.CoinPrice
 		err := r.DB.DB.Order("timestamp").Limit(1).Find(&res).Error
@@ -34,3 +55,13 @@ func (r *Repository) GetBotFirstLaunch() (time.Time, error) {
 	// If we never jumped nor have coin_price (maybe it is the first launch ?) we'll use Now()
 	return time.Now(), nil
 }
+
+func firstTime[T any](rows []T, timestamp func(T) time.Time) (time.Time, bool) {
+	var res time.Time
+	for _, row := range rows {
+		if t := timestamp(row); res.IsZero() || t.Before(res) {
+			res = t
+		}
+	}
+	return res, !res.IsZero()
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/erwanlbp/trading-bot/pkg/model"
 	"github.com/erwanlbp/trading-bot/pkg/util"
@@ -43,14 +44,14 @@ func Pair(from, to string) QueryFilter {
 func (r *Repository) GetLastPairRatiosBefore(t time.Time) ([]model.PairHistory, error) {
 	var res []model.PairHistory
 	err := r.DB.Raw(
-		"with cte as (select ph.*, RANK() OVER (partition by pair_id order by `timestamp` desc) as rnk "+
+		"with cte as (select ph.*, RANK() OVER (partition by pair_id order by timestamp desc) as rnk "+
 			"from pairs_history ph "+
 			"JOIN pairs p on ph.pair_id = p.id "+
 			"JOIN coins fc ON p.from_coin = fc.coin "+
 			"JOIN coins tc ON p.to_coin = tc.coin "+
 			"WHERE ph.timestamp < ? "+
-			"AND fc.enabled = 1 AND tc.enabled = 1) "+
-			"select pair_id, timestamp, ratio from cte where rnk = 1", t).Find(&res).Error
+			"AND fc.enabled = ? AND tc.enabled = ?) "+
+			"select pair_id, timestamp, ratio from cte where rnk = 1", t, true, true).Find(&res).Error
 	return res, err
 }
 
@@ -62,7 +63,7 @@ func (r *Repository) GetAvgLastPairRatioBetween(pairID uint, start, end time.Tim
 
 func ExistingPair() QueryFilter {
 	return func(q *gorm.DB) *gorm.DB {
-		return q.Where("`exists` = 1")
+		return q.Where(`"exists" = ?`, true)
 	}
 }
 
@@ -73,8 +74,19 @@ func ToCoin(coin string) QueryFilter {
 }
 
 func (r *Repository) GetPairRatiosSince(fromCoin, toCoin string, from time.Time) ([]model.PairHistory, error) {
-	var data []model.PairHistory
+	var pairs []model.Pair
+	if err := r.DB.Where("from_coin = ? AND to_coin = ?", fromCoin, toCoin).Find(&pairs).Error; err != nil {
+		return nil, err
+	}
+	archived, err := readArchive[model.PairHistory](r, from)
+	if err != nil {
+		return nil, err
+	}
+	data := util.FilterSlice(archived, func(ph model.PairHistory) bool {
+		return !ph.Timestamp.Before(from) && util.Exists(pairs, func(p model.Pair) bool { return p.ID == ph.PairID })
+	})
 
+	var live []model.PairHistory
 	req := r.DB.DB.Select("ph.*").
 		Table(model.PairTableName+" p").
 		Joins("JOIN "+model.PairHistoryTableName+" ph ON ph.pair_id = p.id").
@@ -82,31 +94,65 @@ func (r *Repository) GetPairRatiosSince(fromCoin, toCoin string, from time.Time)
 		Where("p.to_coin = ?", toCoin).
 		Where("ph.timestamp >= ?", from)
 
-	err := req.Find(&data).Error
+	err = req.Find(&live).Error
 
-	return data, err
+	return append(data, live...), err
 }
 
-func (r *Repository) CleanOldPairHistory() (inserted int64, deleted int64, err error) {
-	if err := r.DB.Transaction(func(tx *gorm.DB) error {
-		resInsert := r.DB.DB.Exec(`
-		INSERT OR REPLACE INTO ` + model.PairHistoryTableName + `(pair_id, timestamp, ratio, averaged)
-		SELECT pair_id, strftime('%Y-%m-%d %H:00:00',timestamp) AS "timestamp" , avg(ratio) AS ratio , 1 FROM ` + model.PairHistoryTableName + ` ph WHERE (ph.averaged IS NULL OR ph.averaged = 0) AND timestamp < strftime('%Y-%m-%d 00:00:00','now')
-		GROUP BY 1,2`)
-		if resInsert.Error != nil {
-			return fmt.Errorf("failed to insert aggregated data: %w", err)
-		}
-		inserted = resInsert.RowsAffected
+// Aggregate the ratios older than the date into buckets of the resolution, pair by pair. Finer rows are replaced by the buckets
+func (r *Repository) DownsamplePairHistory(resolution time.Duration, before time.Time) (inserted int64, deleted int64, err error) {
+	var pairIDs []uint
+	if err := r.DB.Table(model.PairHistoryTableName).Distinct("pair_id").Where("resolution < ? AND timestamp < ?", resolution, before).Find(&pairIDs).Error; err != nil {
+		return 0, 0, fmt.Errorf("failed to get pairs to downsample: %w", err)
+	}
 
-		resDelete := r.DB.DB.Exec(`DELETE FROM ` + model.PairHistoryTableName + ` WHERE (averaged IS NULL OR averaged = 0) AND timestamp < strftime('%Y-%m-%d 00:00:00','now')`)
-		if resDelete.Error != nil {
-			return fmt.Errorf("failed to delete old data after aggregation: %w", err)
+	for _, pairID := range pairIDs {
+		if err := r.DB.Transaction(func(tx *gorm.DB) error {
+			var rows []model.PairHistory
+			if err := tx.Where("pair_id = ? AND resolution < ? AND timestamp < ?", pairID, resolution, before).Order("timestamp").Find(&rows).Error; err != nil {
+				return fmt.Errorf("failed to get rows to downsample: %w", err)
+			}
+			if len(rows) == 0 {
+				return nil
+			}
+			from := rows[0].Timestamp.Truncate(resolution)
+
+			// Buckets already done are merged, a late row could be in one
+			var buckets []model.PairHistory
+			if err := tx.Where("pair_id = ? AND resolution = ? AND timestamp >= ? AND timestamp < ?", pairID, resolution, from, before).Find(&buckets).Error; err != nil {
+				return fmt.Errorf("failed to get existing buckets: %w", err)
+			}
+
+			resDelete := tx.Where("pair_id = ? AND resolution < ? AND timestamp < ?", pairID, resolution, before).Delete(&model.PairHistory{})
+			if resDelete.Error != nil {
+				return fmt.Errorf("failed to delete rows after aggregation: %w", resDelete.Error)
+			}
+			resDeleteBuckets := tx.Where("pair_id = ? AND resolution = ? AND timestamp >= ? AND timestamp < ?", pairID, resolution, from, before).Delete(&model.PairHistory{})
+			if resDeleteBuckets.Error != nil {
+				return fmt.Errorf("failed to delete existing buckets: %w", resDeleteBuckets.Error)
+			}
+
+			newBuckets := model.DownsamplePairHistory(append(rows, buckets...), resolution)
+			if err := tx.CreateInBatches(newBuckets, 500).Error; err != nil {
+				return fmt.Errorf("failed to insert buckets: %w", err)
+			}
+
+			inserted += int64(len(newBuckets)) - resDeleteBuckets.RowsAffected
+			deleted += resDelete.RowsAffected
+			return nil
+		}); err != nil {
+			return inserted, deleted, fmt.Errorf("pair %d: %w", pairID, err)
 		}
-		deleted = resDelete.RowsAffected
+	}
+
+	return inserted, deleted, nil
+}
 
-		return nil
-	}); err != nil {
-		return 0, 0, err
+// Insert the ratios, the ones already there are kept. Returns the number of inserted rows
+func (r *Repository) SaveBackfilledRatios(ratios []model.PairHistory) (int64, error) {
+	if len(ratios) == 0 {
+		return 0, nil
 	}
-	return
+	res := r.DB.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(ratios, 500)
+	return res.RowsAffected, res.Error
 }
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/erwanlbp/trading-bot/pkg/model"
+)
+
+// Return a non paused state if there's none yet
+func (r *Repository) GetPause() (model.Pause, error) {
+	var res model.Pause
+	err := r.DB.Limit(1).Find(&res).Error
+	res.ID = 1
+	return res, err
+}
+
+func (r *Repository) IsPaused() (bool, error) {
+	pause, err := r.GetPause()
+	return pause.IsActive(time.Now().UTC()), err
+}
+
+// A zero until means paused until a manual resume
+func (r *Repository) Pause(reason string, until, now time.Time) error {
+	return SimpleUpsert(r.DB.DB, model.Pause{ID: 1, Paused: true, Reason: reason, PausedOn: now, Until: until})
+}
+
+func (r *Repository) Unpause() error {
+	return SimpleUpsert(r.DB.DB, model.Pause{ID: 1})
+}
+
+func (r *Repository) SetPauseReminder(ts time.Time) error {
+	return r.DB.Model(&model.Pause{}).Where("id = ?", 1).Update("last_reminder", ts).Error
+}
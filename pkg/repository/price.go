@@ -3,6 +3,9 @@ package repository
 import (
 	"time"
 
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm/clause"
+
 	"github.com/erwanlbp/trading-bot/pkg/model"
 	"github.com/erwanlbp/trading-bot/pkg/util"
 )
@@ -26,15 +29,43 @@ func (r *Repository) GetCoinsLastPrice(altCoin string) ([]model.CoinPrice, error
 }
 
 func (r *Repository) GetCoinPricesSince(coins []string, altCoin string, from time.Time) ([]model.CoinPrice, error) {
-	var data []model.CoinPrice
+	filterCoins := len(coins) > 1 && !util.Exists(coins, func(coin string) bool { return coin == "*" })
+
+	archived, err := readArchive[model.CoinPrice](r, from)
+	if err != nil {
+		return nil, err
+	}
+	data := util.FilterSlice(archived, func(p model.CoinPrice) bool {
+		return p.AltCoin == altCoin && !p.Timestamp.Before(from) && (!filterCoins || util.Exists(coins, func(coin string) bool { return coin == p.Coin }))
+	})
 
 	req := r.DB.DB.Where("alt_coin = ?", altCoin).Where("timestamp >= ?", from)
 
-	if len(coins) > 1 && !util.Exists(coins, func(coin string) bool { return coin == "*" }) {
+	if filterCoins {
 		req = req.Where("coin IN ?", coins)
 	}
 
-	err := req.Find(&data).Error
+	var live []model.CoinPrice
+	err = req.Find(&live).Error
+
+	return append(data, live...), err
+}
+
+// Insert the prices, the ones already there are kept. Returns the number of inserted rows
+func (r *Repository) SaveBackfilledPrices(prices []model.CoinPrice) (int64, error) {
+	if len(prices) == 0 {
+		return 0, nil
+	}
+	res := r.DB.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(prices, 500)
+	return res.RowsAffected, res.Error
+}
 
-	return data, err
+// Last price of the coin at the date, zero if there's none. Archived prices are not read
+func (r *Repository) GetCoinPriceAt(coin, altCoin string, t time.Time) (decimal.Decimal, error) {
+	var res []model.CoinPrice
+	err := r.DB.Where("coin = ? AND alt_coin = ? AND timestamp <= ?", coin, altCoin, t).Order("timestamp desc").Limit(1).Find(&res).Error
+	if err != nil || len(res) == 0 {
+		return decimal.Zero, err
+	}
+	return res[0].Last(), nil
 }
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"strings"
+	"time"
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
@@ -50,3 +51,22 @@ func Limit(limit int) QueryFilter {
 		return q.Limit(limit)
 	}
 }
+
+const includeArchiveKey = "repository:include_archive"
+
+// Also read the archived rows since the date, the archive can be big: only for charts and backtests, not on every tick.
+// The other filters only apply to the DB rows
+func IncludeArchive(since time.Time) QueryFilter {
+	return func(q *gorm.DB) *gorm.DB {
+		return q.Set(includeArchiveKey, since)
+	}
+}
+
+func archiveSince(q *gorm.DB) (time.Time, bool) {
+	v, ok := q.Get(includeArchiveKey)
+	if !ok {
+		return time.Time{}, false
+	}
+	since, ok := v.(time.Time)
+	return since, ok
+}
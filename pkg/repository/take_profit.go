@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/erwanlbp/trading-bot/pkg/model"
+)
+
+func (r *Repository) GetTakeProfits(filters ...QueryFilter) ([]model.TakeProfit, error) {
+	var res []model.TakeProfit
+
+	req := r.DB.DB
+
+	for _, f := range filters {
+		req = f(req)
+	}
+
+	err := req.Find(&res).Error
+	return res, err
+}
+
+func (r *Repository) GetRealizedProfit() (decimal.Decimal, error) {
+	var res decimal.Decimal
+	err := r.DB.Select("COALESCE(SUM(realized_profit), 0)").Table(model.TakeProfitTableName).Find(&res).Error
+	return res, err
+}
+
+// Bridge amount banked by take profits and not released yet, that must not be spent buying coins
+func (r *Repository) GetBridgeReserve() (decimal.Decimal, error) {
+	var banked decimal.Decimal
+	if err := r.DB.Select("COALESCE(SUM(proceeds), 0)").Table(model.TakeProfitTableName).Where("banked = ?", true).Find(&banked).Error; err != nil {
+		return decimal.Zero, err
+	}
+	var released decimal.Decimal
+	if err := r.DB.Select("COALESCE(SUM(amount), 0)").Table(model.ReserveReleaseTableName).Find(&released).Error; err != nil {
+		return decimal.Zero, err
+	}
+	return decimal.Max(decimal.Zero, banked.Sub(released)), nil
+}
+
+// Make the amount of the reserve spendable again, nothing is saved if the amount isn't positive
+func (r *Repository) ReleaseBridgeReserve(amount decimal.Decimal, reason string, ts time.Time) error {
+	if !amount.IsPositive() {
+		return nil
+	}
+	return r.DB.Create(&model.ReserveRelease{Timestamp: ts, Amount: amount, Reason: reason}).Error
+}
+
+// The reserve can't be more than the bridge balance, the missing part was withdrawn or spent by hand.
+// It's released, otherwise bridge added later couldn't be spent. Return the capped reserve
+func (r *Repository) CapBridgeReserve(balance decimal.Decimal) (decimal.Decimal, error) {
+	reserve, err := r.GetBridgeReserve()
+	if err != nil {
+		return decimal.Zero, err
+	}
+	balance = decimal.Max(decimal.Zero, balance)
+	if reserve.LessThanOrEqual(balance) {
+		return reserve, nil
+	}
+	if err := r.ReleaseBridgeReserve(reserve.Sub(balance), model.ReserveReleasedCapped, time.Now().UTC()); err != nil {
+		return decimal.Zero, err
+	}
+	return balance, nil
+}
+
+// Return a zero Timestamp if there's no reference yet
+func (r *Repository) GetPositionReference() (model.PositionReference, error) {
+	var res model.PositionReference
+	err := r.DB.Limit(1).Find(&res).Error
+	return res, err
+}
+
+func (r *Repository) SetPositionReference(value decimal.Decimal, ts time.Time) error {
+	return SimpleUpsert(r.DB.DB, model.PositionReference{ID: 1, Timestamp: ts, Value: value})
+}
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/erwanlbp/trading-bot/pkg/model"
+)
+
+func (r *Repository) CountUniverseChangesSince(since time.Time) (int64, error) {
+	var res int64
+	err := r.DB.Model(&model.UniverseChange{}).Where("timestamp >= ?", since).Count(&res).Error
+	return res, err
+}
+
+func (r *Repository) SaveUniverseChanges(changes ...model.UniverseChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	return r.DB.Create(&changes).Error
+}
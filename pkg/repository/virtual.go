@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/erwanlbp/trading-bot/pkg/model"
+	"github.com/erwanlbp/trading-bot/pkg/util"
+)
+
+func Strategy(name string) QueryFilter {
+	return func(q *gorm.DB) *gorm.DB {
+		return q.Where("strategy = ?", name)
+	}
+}
+
+// Return false if the strategy has no portfolio yet
+func (r *Repository) GetVirtualPortfolio(strategy string) (model.VirtualPortfolio, bool, error) {
+	var res []model.VirtualPortfolio
+	err := r.DB.DB.Scopes(Strategy(strategy)).Limit(1).Find(&res).Error
+	if err != nil || len(res) == 0 {
+		return model.VirtualPortfolio{}, false, err
+	}
+	return res[0], true, nil
+}
+
+func (r *Repository) GetVirtualPairs(strategy string) (map[string]model.VirtualPair, error) {
+	var res []model.VirtualPair
+	err := r.DB.DB.Scopes(Strategy(strategy)).Find(&res).Error
+	if err != nil {
+		return nil, err
+	}
+	return util.AsMap(res, func(p model.VirtualPair) string { return util.Symbol(p.FromCoin, p.ToCoin) }), nil
+}
+
+func (r *Repository) GetVirtualJumps(filters ...QueryFilter) ([]model.VirtualJump, error) {
+	var res []model.VirtualJump
+
+	req := r.DB.DB
+
+	for _, f := range filters {
+		req = f(req)
+	}
+
+	err := req.Find(&res).Error
+	return res, err
+}
+
+func (r *Repository) GetVirtualDiff(filters ...QueryFilter) ([]model.VirtualDiff, error) {
+	var res []model.VirtualDiff
+
+	req := r.DB.DB
+
+	for _, f := range filters {
+		req = f(req)
+	}
+
+	err := req.Find(&res).Error
+	return res, err
+}
+
+func (r *Repository) ReplaceAllVirtualDiff(strategy string, computedDiff []model.VirtualDiff) error {
+	if err := r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Scopes(Strategy(strategy)).Delete(&model.VirtualDiff{}).Error; err != nil {
+			return fmt.Errorf("failed deleting diff: %w", err)
+		}
+		if err := SimpleUpsert(tx, computedDiff...); err != nil {
+			return fmt.Errorf("failed saving computed diff: %w", err)
+		}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed updating virtual diff: %s", err)
+	}
+	return nil
+}
+
+// Save the virtual jump, the new portfolio and the pairs ratios updated after the jump, all at once
+func (r *Repository) SaveVirtualJump(jump model.VirtualJump, portfolio model.VirtualPortfolio, pairs []model.VirtualPair) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := SimpleUpsert(tx, jump); err != nil {
+			return fmt.Errorf("failed saving virtual jump: %w", err)
+		}
+		if err := SimpleUpsert(tx, portfolio); err != nil {
+			return fmt.Errorf("failed saving virtual portfolio: %w", err)
+		}
+		if err := SimpleUpsert(tx, pairs...); err != nil {
+			return fmt.Errorf("failed saving virtual pairs: %w", err)
+		}
+		return nil
+	})
+}
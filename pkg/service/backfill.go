@@ -0,0 +1,210 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/erwanlbp/trading-bot/pkg/binance"
+	"github.com/erwanlbp/trading-bot/pkg/model"
+	"github.com/erwanlbp/trading-bot/pkg/repository"
+	"github.com/erwanlbp/trading-bot/pkg/util"
+)
+
+// Kline intervals that can be backfilled
+var BackfillIntervals = map[string]time.Duration{
+	"1m": time.Minute,
+	"5m": 5 * time.Minute,
+}
+
+// Longest range of a backfill, a longer history can be backfilled in several runs
+const MaxBackfillRange = 90 * 24 * time.Hour
+
+// Klines of all the coins are downloaded and saved a window at a time, only one window is kept in memory
+const backfillWindow = 24 * time.Hour
+
+type BackfillRequest struct {
+	Coins    []string
+	Interval string
+	From     time.Time
+	To       time.Time
+}
+
+func (r BackfillRequest) Validate() error {
+	if len(r.Coins) == 0 {
+		return errors.New("no coin to backfill")
+	}
+	if _, ok := BackfillIntervals[r.Interval]; !ok {
+		return fmt.Errorf("interval must be one of %s", strings.Join(util.Keys(BackfillIntervals), ", "))
+	}
+	if !r.From.Before(r.To) {
+		return errors.New("from must be before to")
+	}
+	if r.To.Sub(r.From) > MaxBackfillRange {
+		return fmt.Errorf("range can't be longer than %d days", int(MaxBackfillRange.Hours()/24))
+	}
+	return nil
+}
+
+type BackfillResult struct {
+	// Requested coins and the coins they're paired with, needed for the ratios
+	Coins  []string
+	From   time.Time
+	To     time.Time
+	Prices int64
+	Ratios int64
+}
+
+func (r BackfillResult) Describe() string {
+	return fmt.Sprintf("Backfilled %d prices and %d ratios of %s, from %s to %s",
+		r.Prices, r.Ratios, strings.Join(r.Coins, ","), r.From.Format(time.DateTime), r.To.Format(time.DateTime))
+}
+
+// Download the klines of the coins, save them as prices and ratios marked as backfilled.
+//
+// Prices already collected by the bot are kept. The range ends before the last collected prices, backfilled ones must not be taken as the current prices
+func (s *Service) Backfill(ctx context.Context, req BackfillRequest) (BackfillResult, error) {
+	if err := req.Validate(); err != nil {
+		return BackfillResult{}, err
+	}
+	bridge := s.ConfigFile.Bridge
+	resolution := BackfillIntervals[req.Interval]
+
+	lastPrices, err := s.Repository.GetCoinsLastPrice(bridge)
+	if err != nil {
+		return BackfillResult{}, fmt.Errorf("failed getting last prices: %w", err)
+	}
+	if len(lastPrices) > 0 && lastPrices[0].Timestamp.Before(req.To) {
+		req.To = lastPrices[0].Timestamp
+	}
+	req.From = req.From.Truncate(resolution)
+	if !req.From.Before(req.To) {
+		return BackfillResult{}, errors.New("nothing to backfill before the prices collected by the bot")
+	}
+
+	requested := util.AsSet(req.Coins, util.Identity[string]())
+	allPairs, err := s.Repository.GetPairs(repository.ExistingPair())
+	if err != nil {
+		return BackfillResult{}, fmt.Errorf("failed getting pairs: %w", err)
+	}
+	var pairs []model.Pair
+	coins := make(map[string]bool)
+	for coin := range requested {
+		coins[coin] = true
+	}
+	for _, pair := range allPairs {
+		if requested[pair.FromCoin] || requested[pair.ToCoin] {
+			pairs = append(pairs, pair)
+			coins[pair.FromCoin] = true
+			coins[pair.ToCoin] = true
+		}
+	}
+	delete(coins, bridge)
+
+	res := BackfillResult{Coins: util.Keys(coins), From: req.From, To: req.To}
+	sort.Strings(res.Coins)
+
+	for windowStart := req.From; windowStart.Before(req.To); windowStart = windowStart.Add(backfillWindow) {
+		windowEnd := windowStart.Add(backfillWindow)
+		if windowEnd.After(req.To) {
+			windowEnd = req.To
+		}
+		if err := s.backfillWindow(ctx, &res, pairs, req.Interval, windowStart, windowEnd); err != nil {
+			return res, err
+		}
+	}
+
+	return res, nil
+}
+
+// Backfill the prices of the coins of the result between start and end, then the ratios of the pairs
+func (s *Service) backfillWindow(ctx context.Context, res *BackfillResult, pairs []model.Pair, interval string, start, end time.Time) error {
+	bridge := s.ConfigFile.Bridge
+	resolution := BackfillIntervals[interval]
+
+	// Prices of each coin by kline open time, to derive the ratios
+	prices := make(map[string]map[int64]model.CoinPrice)
+	for _, coin := range res.Coins {
+		klines, err := s.Binance.GetKlines(ctx, coin, bridge, interval, start, end)
+		if err != nil {
+			return err
+		}
+
+		var rows []model.CoinPrice
+		prices[coin] = make(map[int64]model.CoinPrice)
+		for _, kline := range klines {
+			price := BackfilledPrice(kline, resolution)
+			rows = append(rows, price)
+			prices[coin][kline.OpenTime.UnixMilli()] = price
+		}
+		inserted, err := s.Repository.SaveBackfilledPrices(rows)
+		if err != nil {
+			return fmt.Errorf("failed saving %s prices: %w", coin, err)
+		}
+		res.Prices += inserted
+		s.Logger.Debug(fmt.Sprintf("Backfilled %s from %s: %d klines, %d new prices, %d weight used this minute", coin, start.Format(time.DateTime), len(klines), inserted, s.Binance.UsedWeight()))
+	}
+
+	for _, pair := range pairs {
+		var rows []model.PairHistory
+		for openTime, fromPrice := range prices[pair.FromCoin] {
+			toPrice, ok := prices[pair.ToCoin][openTime]
+			if !ok || !toPrice.Price.IsPositive() || !toPrice.Last().IsPositive() {
+				continue
+			}
+			rows = append(rows, BackfilledRatio(pair.ID, fromPrice, toPrice))
+		}
+		inserted, err := s.Repository.SaveBackfilledRatios(rows)
+		if err != nil {
+			return fmt.Errorf("failed saving %s ratios: %w", pair.LogSymbol(), err)
+		}
+		res.Ratios += inserted
+	}
+
+	return nil
+}
+
+// A 1m kline is a raw price, like GetSymbolPriceAtTime. Coarser ones are buckets, the bot collects a price per minute
+func BackfilledPrice(kline binance.Kline, resolution time.Duration) model.CoinPrice {
+	res := model.CoinPrice{
+		Coin:       kline.Coin,
+		AltCoin:    kline.AltCoin,
+		Timestamp:  kline.OpenTime,
+		Price:      kline.Open.Add(kline.Close).Div(decimal.NewFromInt(2)),
+		Backfilled: true,
+	}
+	if resolution > time.Minute {
+		res.Averaged = true
+		res.Resolution = resolution
+		res.Samples = int64(resolution / time.Minute)
+		res.MinPrice = kline.Low
+		res.MaxPrice = kline.High
+		res.LastPrice = kline.Close
+	}
+	return res
+}
+
+// Klines don't tell when the lows and highs of both coins happened, bucket bounds are the ratios we know
+func BackfilledRatio(pairID uint, from, to model.CoinPrice) model.PairHistory {
+	res := model.PairHistory{
+		PairID:     pairID,
+		Timestamp:  from.Timestamp,
+		Ratio:      from.Price.Div(to.Price),
+		Averaged:   from.Averaged,
+		Resolution: from.Resolution,
+		Samples:    from.Samples,
+		Backfilled: true,
+	}
+	if res.Resolution > 0 {
+		last := from.Last().Div(to.Last())
+		res.MinRatio = decimal.Min(res.Ratio, last)
+		res.MaxRatio = decimal.Max(res.Ratio, last)
+		res.LastRatio = last
+	}
+	return res
+}
@@ -0,0 +1,62 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/erwanlbp/trading-bot/pkg/binance"
+	"github.com/erwanlbp/trading-bot/pkg/service"
+)
+
+func TestBackfilledHistory(t *testing.T) {
+	t.Parallel()
+
+	openTime := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	kline := func(coin, open, high, low, close string) binance.Kline {
+		return binance.Kline{
+			Coin:     coin,
+			AltCoin:  "USDT",
+			OpenTime: openTime,
+			Open:     decimal.RequireFromString(open),
+			High:     decimal.RequireFromString(high),
+			Low:      decimal.RequireFromString(low),
+			Close:    decimal.RequireFromString(close),
+		}
+	}
+	eth := kline("ETH", "3000", "3100", "2900", "3050")
+	btc := kline("BTC", "60000", "61000", "59000", "61000")
+
+	raw := service.BackfilledPrice(eth, time.Minute)
+	assert.True(t, raw.Backfilled)
+	assert.Zero(t, raw.Resolution)
+	assert.Equal(t, "3025", raw.Price.String())
+	assert.Equal(t, "3025", raw.Max().String())
+
+	ethBucket := service.BackfilledPrice(eth, 5*time.Minute)
+	assert.Equal(t, 5*time.Minute, ethBucket.Resolution)
+	assert.Equal(t, int64(5), ethBucket.Samples)
+	assert.Equal(t, "2900", ethBucket.Min().String())
+	assert.Equal(t, "3100", ethBucket.Max().String())
+	assert.Equal(t, "3050", ethBucket.Last().String())
+
+	ratio := service.BackfilledRatio(1, ethBucket, service.BackfilledPrice(btc, 5*time.Minute))
+	assert.True(t, ratio.Backfilled)
+	assert.True(t, ratio.Timestamp.Equal(openTime))
+	assert.Equal(t, "0.05", ratio.Ratio.String())
+	assert.Equal(t, "0.05", ratio.Max().String())
+	assert.Equal(t, "0.05", ratio.Last().String())
+}
+
+func TestBackfillRequestValidate(t *testing.T) {
+	t.Parallel()
+
+	to := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	req := service.BackfillRequest{Coins: []string{"ETH"}, Interval: "1m", From: to.Add(-service.MaxBackfillRange), To: to}
+	assert.NoError(t, req.Validate())
+
+	req.From = req.From.Add(-time.Minute)
+	assert.Error(t, req.Validate())
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/erwanlbp/trading-bot/pkg/model"
+)
+
+// Last n completed holdings (at most) of each coin, oldest first, with the result of the other coins over the same time
+func (s *Service) GetLastHoldings(n int) (map[string][]model.Holding, error) {
+	res, err := s.Repository.GetLastHoldings(n)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get last holdings: %w", err)
+	}
+
+	enabledCoins, err := s.Repository.GetEnabledCoins()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get enabled coins: %w", err)
+	}
+
+	s.holdingsMtx.Lock()
+	defer s.holdingsMtx.Unlock()
+	if s.marketResults == nil {
+		s.marketResults = make(map[string]decimal.Decimal)
+	}
+
+	for coin, holdings := range res {
+		for i, h := range holdings {
+			// A holding doesn't change once completed, the prices are only looked up once
+			key := h.Coin + "@" + h.EnteredOn.Format(time.RFC3339Nano)
+			market, ok := s.marketResults[key]
+			if !ok {
+				if market, err = s.marketResult(h, enabledCoins); err != nil {
+					return nil, err
+				}
+				s.marketResults[key] = market
+			}
+			res[coin][i].MarketResult = market
+		}
+	}
+	return res, nil
+}
+
+// Average evolution (in %) of the other coins during the holding, zero if their prices are unknown
+func (s *Service) marketResult(h model.Holding, coins []string) (decimal.Decimal, error) {
+	sum, count := decimal.Zero, 0
+	for _, coin := range coins {
+		if coin == h.Coin {
+			continue
+		}
+		entry, err := s.Repository.GetCoinPriceAt(coin, s.ConfigFile.Bridge, h.EnteredOn)
+		if err != nil {
+			return decimal.Zero, fmt.Errorf("failed to get %s price: %w", coin, err)
+		}
+		exit, err := s.Repository.GetCoinPriceAt(coin, s.ConfigFile.Bridge, h.ExitedOn)
+		if err != nil {
+			return decimal.Zero, fmt.Errorf("failed to get %s price: %w", coin, err)
+		}
+		if !entry.IsPositive() || !exit.IsPositive() {
+			continue
+		}
+		sum = sum.Add(exit.Div(entry))
+		count++
+	}
+	if count == 0 {
+		return decimal.Zero, nil
+	}
+	return sum.Div(decimal.NewFromInt(int64(count))).Sub(decimal.NewFromInt(1)).Mul(decimal.NewFromInt(100)), nil
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/erwanlbp/trading-bot/pkg/binance"
 	"github.com/erwanlbp/trading-bot/pkg/model"
@@ -26,11 +27,27 @@ func (s *Service) InitializePairs(ctx context.Context) error {
 		return fmt.Errorf("failed getting existing pairs: %w", err)
 	}
 
-	jumps, err := s.Repository.GetJumps()
+	// Only on start and reload, the last jump to a coin can be archived
+	jumps, err := s.Repository.GetJumps(repository.IncludeArchive(time.Time{}))
 	if err != nil {
 		return fmt.Errorf("failed to get jumps: %w", err)
 	}
 
+	// Current prices and spreads: jump ratios are stored on the bid/ask basis the jump finder compares them to
+	var prices map[string]binance.CoinPrice
+	var tickers map[string]binance.BookTicker
+	if len(coins) > 0 {
+		prices, err = s.Binance.GetCoinsPrice(ctx, coins, []string{s.ConfigFile.Bridge})
+		if err != nil {
+			return fmt.Errorf("failed getting coins prices: %w", err)
+		}
+		// Not blocking, ratios will be based on last prices
+		tickers, err = s.Binance.GetCoinsBookTicker(ctx, coins, []string{s.ConfigFile.Bridge})
+		if err != nil {
+			s.Logger.Warn("Failed to get book tickers, initial ratios will be based on last prices", zap.Error(err))
+		}
+	}
+
 	lastJumpToCoin := make(map[string]model.Jump)
 	for _, jump := range jumps {
 		// Safety check
@@ -99,7 +116,7 @@ func (s *Service) InitializePairs(ctx context.Context) error {
 				}
 
 				if !toPrice.Price.Equal(decimal.Zero) {
-					pair.LastJumpRatio = fromPrice.Price.Div(toPrice.Price)
+					pair.LastJumpRatio = fromPrice.Price.Div(toPrice.Price).Mul(spreadMultiplier(pair.FromCoin, pair.ToCoin, s.ConfigFile.Bridge, prices, tickers))
 					pair.LastJumpRatioBasedOn = lastJump.Timestamp
 					pairsNeedingLastJumpPriceToSave = append(pairsNeedingLastJumpPriceToSave, pair)
 				}
@@ -117,15 +134,11 @@ func (s *Service) InitializePairs(ctx context.Context) error {
 		if err != nil {
 			return fmt.Errorf("failed to get bot first launch datetime: %w", err)
 		}
-		prices, err := s.Binance.GetCoinsPrice(ctx, coinsNeedingBotStartPrice, []string{s.ConfigFile.Bridge})
-		if err != nil {
-			return fmt.Errorf("failed getting coins prices: %w", err)
-		}
 		for i, pair := range pairsNeedingBotStartPriceToSave {
 			fromPrice := prices[util.Symbol(pair.FromCoin, s.ConfigFile.Bridge)].Price
 			toPrice := prices[util.Symbol(pair.ToCoin, s.ConfigFile.Bridge)].Price
 			if !toPrice.Equal(decimal.Zero) {
-				pair.LastJumpRatio = fromPrice.Div(toPrice)
+				pair.LastJumpRatio = fromPrice.Div(toPrice).Mul(spreadMultiplier(pair.FromCoin, pair.ToCoin, s.ConfigFile.Bridge, prices, tickers))
 				pair.LastJumpRatioBasedOn = botStartTime
 				pairsNeedingBotStartPriceToSave[i] = pair
 			}
@@ -140,3 +153,15 @@ func (s *Service) InitializePairs(ctx context.Context) error {
 
 	return nil
 }
+
+// Turn a ratio of last prices into the one we'd get selling from_coin at bid and buying to_coin at ask, with the current spreads.
+// 1 if a price or the book ticker is missing
+func spreadMultiplier(fromCoin, toCoin, bridge string, prices map[string]binance.CoinPrice, tickers map[string]binance.BookTicker) decimal.Decimal {
+	fromSymbol, toSymbol := util.Symbol(fromCoin, bridge), util.Symbol(toCoin, bridge)
+	last, lastTo := prices[fromSymbol].Price, prices[toSymbol].Price
+	bid, ask := tickers[fromSymbol].BidPrice, tickers[toSymbol].AskPrice
+	if !last.IsPositive() || !lastTo.IsPositive() || !bid.IsPositive() || !ask.IsPositive() {
+		return decimal.NewFromInt(1)
+	}
+	return bid.Div(last).Mul(lastTo.Div(ask))
+}
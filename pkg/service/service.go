@@ -1,6 +1,10 @@
 package service
 
 import (
+	"sync"
+
+	"github.com/shopspring/decimal"
+
 	"github.com/erwanlbp/trading-bot/pkg/binance"
 	"github.com/erwanlbp/trading-bot/pkg/config/configfile"
 	"github.com/erwanlbp/trading-bot/pkg/log"
@@ -12,6 +16,10 @@ type Service struct {
 	Repository *repository.Repository
 	Binance    *binance.Client
 	ConfigFile *configfile.ConfigFile
+
+	// Result of the other coins during each completed holding
+	marketResults map[string]decimal.Decimal
+	holdingsMtx   sync.Mutex
 }
 
 func NewService(l *log.Logger, r *repository.Repository, b *binance.Client, cf *configfile.ConfigFile) *Service {
@@ -0,0 +1,220 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/erwanlbp/trading-bot/pkg/binance"
+	"github.com/erwanlbp/trading-bot/pkg/config/configfile"
+	"github.com/erwanlbp/trading-bot/pkg/util"
+)
+
+type RankedCoin struct {
+	Coin  string
+	Score decimal.Decimal
+	Stats binance.TickerStats
+}
+
+type UniverseProposal struct {
+	// Best coins first
+	Ranking []RankedCoin
+	// New coin list
+	Coins   []string
+	Added   []string
+	Removed []string
+	// Held coin that should have been removed
+	Kept string
+	// Changes not done because of the daily limit
+	Postponed int
+}
+
+func (p UniverseProposal) HasChanges() bool {
+	return len(p.Added) > 0 || len(p.Removed) > 0
+}
+
+// Changes of the proposal, one per line
+func (p UniverseProposal) Describe() []string {
+	res := []string{"🌍 New coin list proposed"}
+	if len(p.Added) > 0 {
+		res = append(res, "Added: "+strings.Join(p.Added, ", "))
+	}
+	if len(p.Removed) > 0 {
+		res = append(res, "Removed: "+strings.Join(p.Removed, ", "))
+	}
+	if p.Kept != "" {
+		res = append(res, fmt.Sprintf("Kept %s as it's the current coin", p.Kept))
+	}
+	if p.Postponed > 0 {
+		res = append(res, fmt.Sprintf("%d change(s) postponed by the daily limit", p.Postponed))
+	}
+	return res
+}
+
+// Rank the coins against the current coin list, changes are limited by what's left of the daily limit
+func (s *Service) ProposeUniverse(ctx context.Context) (UniverseProposal, error) {
+	stats := s.Binance.GetTickerStats(ctx)
+	if len(stats) == 0 {
+		return UniverseProposal{}, fmt.Errorf("no 24h ticker stats")
+	}
+
+	currentCoin, hasEverJumped, err := s.Repository.GetCurrentCoin()
+	if err != nil {
+		return UniverseProposal{}, fmt.Errorf("failed getting current coin: %w", err)
+	}
+	var heldCoin string
+	if hasEverJumped {
+		heldCoin = currentCoin.Coin
+	}
+
+	now := time.Now().UTC()
+	changesDone, err := s.Repository.CountUniverseChangesSince(now.Truncate(24 * time.Hour))
+	if err != nil {
+		return UniverseProposal{}, fmt.Errorf("failed counting today's changes: %w", err)
+	}
+
+	conf := s.ConfigFile.Universe
+	ranking := RankCoins(stats, s.ConfigFile.Bridge, conf, s.ConfigFile.Liquidity)
+
+	return SelectUniverse(ranking, s.ConfigFile.Coins, heldCoin, conf, conf.MaxChangesPerDay-int(changesDone)), nil
+}
+
+// Score the coins quoted in the bridge, best first. Excluded and illiquid coins are left out.
+//
+// There's no supply data in the tickers, so the market cap proxy is the volume needed to move the price by 1%: big caps trade a lot while moving little
+func RankCoins(stats map[string]binance.TickerStats, bridge string, conf configfile.Universe, liquidity configfile.Liquidity) []RankedCoin {
+	excluded := util.AsSet(conf.Exclude, util.Identity[string]())
+
+	var res []RankedCoin
+	for symbol, s := range stats {
+		coin, found := strings.CutSuffix(symbol, bridge)
+		if !found || coin == "" || excluded[coin] {
+			continue
+		}
+		// Delisted symbols are still in the tickers, without trades
+		if s.NbTrades == 0 || !s.QuoteVolume.IsPositive() || !s.LowPrice.IsPositive() {
+			continue
+		}
+		if s.CheckLiquidity(liquidity.MinQuoteVolume, liquidity.MaxSpread) != "" {
+			continue
+		}
+
+		// Range of the day, in %
+		volatility := s.HighPrice.Sub(s.LowPrice).Div(s.LowPrice).Mul(decimal.NewFromInt(100))
+
+		var score decimal.Decimal
+		switch conf.RankBy {
+		case configfile.RankByVolume:
+			score = s.QuoteVolume
+		case configfile.RankByMarketCap:
+			score = s.QuoteVolume.Div(decimal.Max(volatility, decimal.NewFromFloat(0.1)))
+		case configfile.RankByVolatility:
+			score = volatility
+		}
+
+		res = append(res, RankedCoin{Coin: coin, Score: score, Stats: s})
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		if !res[i].Score.Equal(res[j].Score) {
+			return res[i].Score.GreaterThan(res[j].Score)
+		}
+		return res[i].Coin < res[j].Coin
+	})
+
+	return res
+}
+
+// Compute the new coin list: included coins then the best ranked ones, up to the size.
+//
+// Removals and additions alternate until changesLeft is reached, and the held coin is never removed
+func SelectUniverse(ranking []RankedCoin, current []string, heldCoin string, conf configfile.Universe, changesLeft int) UniverseProposal {
+	res := UniverseProposal{Ranking: ranking}
+
+	wanted := make(map[string]bool)
+	var toAdd []string
+	for _, coin := range conf.Include {
+		if !wanted[coin] {
+			wanted[coin] = true
+			toAdd = append(toAdd, coin)
+		}
+	}
+	for _, ranked := range ranking {
+		if len(wanted) >= conf.Size {
+			break
+		}
+		if !wanted[ranked.Coin] {
+			wanted[ranked.Coin] = true
+			toAdd = append(toAdd, ranked.Coin)
+		}
+	}
+
+	currentSet := util.AsSet(current, util.Identity[string]())
+	toAdd = util.FilterSlice(toAdd, func(coin string) bool { return !currentSet[coin] })
+
+	// Worst coins are removed first
+	rank := make(map[string]int)
+	for i, ranked := range ranking {
+		rank[ranked.Coin] = i + 1
+	}
+	var toRemove []string
+	for _, coin := range current {
+		if wanted[coin] {
+			continue
+		}
+		if coin == heldCoin {
+			res.Kept = coin
+			continue
+		}
+		toRemove = append(toRemove, coin)
+	}
+	sort.Slice(toRemove, func(i, j int) bool {
+		ri, rj := rank[toRemove[i]], rank[toRemove[j]]
+		switch {
+		// Unranked coins are the worst
+		case ri == 0 && rj == 0:
+			return toRemove[i] < toRemove[j]
+		case ri == 0:
+			return true
+		case rj == 0:
+			return false
+		default:
+			return ri > rj
+		}
+	})
+
+	removed := make(map[string]bool)
+	for i := 0; i < len(toAdd) || i < len(toRemove); i++ {
+		if i < len(toRemove) {
+			if changesLeft > 0 {
+				res.Removed = append(res.Removed, toRemove[i])
+				removed[toRemove[i]] = true
+				changesLeft--
+			} else {
+				res.Postponed++
+			}
+		}
+		if i < len(toAdd) {
+			if changesLeft > 0 {
+				res.Added = append(res.Added, toAdd[i])
+				changesLeft--
+			} else {
+				res.Postponed++
+			}
+		}
+	}
+
+	for _, coin := range current {
+		if !removed[coin] {
+			res.Coins = append(res.Coins, coin)
+		}
+	}
+	res.Coins = append(res.Coins, res.Added...)
+	sort.Strings(res.Coins)
+
+	return res
+}
@@ -0,0 +1,64 @@
+package service_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/erwanlbp/trading-bot/pkg/config/configfile"
+	"github.com/erwanlbp/trading-bot/pkg/service"
+)
+
+func TestSelectUniverse(t *testing.T) {
+	t.Parallel()
+
+	var ranking []service.RankedCoin
+	for _, coin := range []string{"AAA", "BBB", "CCC", "DDD", "EEE"} {
+		ranking = append(ranking, service.RankedCoin{Coin: coin})
+	}
+	conf := configfile.Universe{Size: 3, Include: []string{"ZZZ"}}
+
+	for _, c := range []struct {
+		name        string
+		current     []string
+		held        string
+		changesLeft int
+		expected    service.UniverseProposal
+	}{
+		{
+			name:        "up to date",
+			current:     []string{"AAA", "BBB", "ZZZ"},
+			changesLeft: 2,
+			expected:    service.UniverseProposal{Coins: []string{"AAA", "BBB", "ZZZ"}},
+		},
+		{
+			name:        "worst coin swapped",
+			current:     []string{"AAA", "DDD", "EEE", "ZZZ"},
+			changesLeft: 4,
+			expected:    service.UniverseProposal{Coins: []string{"AAA", "BBB", "ZZZ"}, Added: []string{"BBB"}, Removed: []string{"EEE", "DDD"}},
+		},
+		{
+			name:        "daily limit",
+			current:     []string{"AAA", "DDD", "EEE", "ZZZ"},
+			changesLeft: 1,
+			expected:    service.UniverseProposal{Coins: []string{"AAA", "DDD", "ZZZ"}, Removed: []string{"EEE"}, Postponed: 2},
+		},
+		{
+			name:        "held coin kept",
+			current:     []string{"AAA", "BBB", "EEE", "ZZZ"},
+			held:        "EEE",
+			changesLeft: 2,
+			expected:    service.UniverseProposal{Coins: []string{"AAA", "BBB", "EEE", "ZZZ"}, Kept: "EEE"},
+		},
+	} {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual := service.SelectUniverse(ranking, c.current, c.held, conf, c.changesLeft)
+			actual.Ranking = nil
+
+			assert.Equal(t, c.expected, actual)
+		})
+	}
+}
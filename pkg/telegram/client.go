@@ -20,7 +20,12 @@ type Client struct {
 	Logger *log.Logger
 	Chat   *telebot.Chat
 
-	queueCh chan string
+	queueCh chan queuedMessage
+}
+
+type queuedMessage struct {
+	text string
+	opts []interface{}
 }
 
 // Documentation : https://github.com/tucnak/telebot
@@ -30,7 +35,7 @@ func NewClient(ctx context.Context, l *log.Logger, cf *configfile.ConfigFile) (*
 		ParseMode: telebot.ModeMarkdown,
 		Poller: &telebot.LongPoller{
 			Timeout:        10 * time.Second,
-			AllowedUpdates: []string{"message", "chosen_inline_result", "inline_query"},
+			AllowedUpdates: []string{"message", "chosen_inline_result", "inline_query", "callback_query"},
 		},
 		OnError: func(err error, ctx telebot.Context) {
 			l.Logger.Error("Error in bot", zap.Error(err))
@@ -55,7 +60,7 @@ func NewClient(ctx context.Context, l *log.Logger, cf *configfile.ConfigFile) (*
 		client:  b,
 		Logger:  l,
 		Chat:    chat,
-		queueCh: make(chan string, 1000),
+		queueCh: make(chan queuedMessage, 1000),
 	}
 
 	go client.queueHandler(ctx)
@@ -64,10 +69,15 @@ func NewClient(ctx context.Context, l *log.Logger, cf *configfile.ConfigFile) (*
 }
 
 func (c *Client) Send(message string) {
+	c.SendWithOptions(message)
+}
+
+// Same as Send, with telebot options, like a *telebot.ReplyMarkup for inline buttons
+func (c *Client) SendWithOptions(message string, opts ...interface{}) {
 	if c.queueCh == nil {
 		return
 	}
-	c.queueCh <- message
+	c.queueCh <- queuedMessage{text: message, opts: opts}
 }
 
 func (c *Client) queueHandler(ctx context.Context) {
@@ -75,7 +85,7 @@ func (c *Client) queueHandler(ctx context.Context) {
 		select {
 		case message := <-c.queueCh:
 			for {
-				_, err := c.client.Send(c.Chat, message, telebot.ModeMarkdown)
+				_, err := c.client.Send(c.Chat, message.text, append([]interface{}{telebot.ModeMarkdown}, message.opts...)...)
 
 				// In case of 429, retry after waiting
 				var floodErr telebot.FloodError
@@ -87,7 +97,7 @@ func (c *Client) queueHandler(ctx context.Context) {
 
 				// In case of other error, log the error and ignore this message
 				if err != nil {
-					c.Logger.Error(fmt.Sprintf("Failed to send message '%s' to telegram", message), zap.Error(err))
+					c.Logger.Error(fmt.Sprintf("Failed to send message '%s' to telegram", message.text), zap.Error(err))
 				}
 
 				break
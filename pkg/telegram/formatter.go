@@ -1,5 +1,14 @@
 package telegram
 
+import "strings"
+
+var mdEscaper = strings.NewReplacer("_", "\\_", "*", "\\*", "`", "\\`", "[", "\\[")
+
+// Escape the characters of the Markdown parse mode, for text that isn't in a code block
+func EscapeMD(s string) string {
+	return mdEscaper.Replace(s)
+}
+
 func FormatForMD(s string) string {
 	return "\n```\n" + s + "\n```\n"
 }
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"gopkg.in/telebot.v3"
+
+	"github.com/erwanlbp/trading-bot/pkg/service"
+)
+
+const backfillUsage = "Usage: /backfill COIN1,COIN2 DAYS [1m|5m], 90 days at most"
+
+// Only one at a time, they share the Binance weight budget
+var backfillRunning atomic.Bool
+
+func (p *Handlers) Backfill(c telebot.Context) error {
+	args := c.Args()
+	if len(args) < 2 || len(args) > 3 {
+		return c.Send(backfillUsage)
+	}
+	days, err := strconv.Atoi(args[1])
+	if err != nil || days <= 0 {
+		return c.Send(fmt.Sprintf("couldn't parse days (%s)\n%s", args[1], backfillUsage))
+	}
+	interval := "1m"
+	if len(args) == 3 {
+		interval = args[2]
+	}
+
+	now := time.Now().UTC()
+	req := service.BackfillRequest{
+		Coins:    strings.Split(strings.ToUpper(args[0]), ","),
+		Interval: interval,
+		From:     now.AddDate(0, 0, -days),
+		To:       now,
+	}
+	if err := req.Validate(); err != nil {
+		return c.Send(err.Error() + "\n" + backfillUsage)
+	}
+
+	if !backfillRunning.CompareAndSwap(false, true) {
+		return c.Send("A backfill is already running")
+	}
+	go func() {
+		defer backfillRunning.Store(false)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 6*time.Hour)
+		defer cancel()
+
+		res, err := p.GlobalConf.Backfill(ctx, req)
+		if err != nil {
+			p.Logger.Warn("Backfill failed: "+res.Describe(), zap.Error(err))
+			return
+		}
+		p.Logger.Info("📥 " + res.Describe())
+	}()
+
+	return c.Send(fmt.Sprintf("Backfilling %s %s klines of the last %d days, you'll be notified when it's done", args[0], interval, days))
+}
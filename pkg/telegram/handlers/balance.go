@@ -11,6 +11,7 @@ import (
 	"github.com/wcharczuk/go-chart/v2"
 	"gopkg.in/telebot.v3"
 
+	"github.com/erwanlbp/trading-bot/pkg/config/configfile"
 	"github.com/erwanlbp/trading-bot/pkg/constant"
 	"github.com/erwanlbp/trading-bot/pkg/telegram"
 	"github.com/erwanlbp/trading-bot/pkg/util"
@@ -162,7 +163,18 @@ func (p *Handlers) ShowBalances(c telebot.Context, alt string) error {
 		return []string{line.Coin, line.Balance.String(), value}
 	})
 
-	return c.Send(telegram.FormatForMD(message), selector)
+	message = telegram.FormatForMD(message)
+
+	// Show the virtual portfolio next to the real one
+	if p.Conf.Shadow.Enabled {
+		summary, err := p.VirtualPortfolioSummary(configfile.ShadowStrategyName)
+		if err != nil {
+			summary = "Failed to get shadow portfolio: " + err.Error()
+		}
+		message = message + "\n" + telegram.EscapeMD(summary)
+	}
+
+	return c.Send(message, selector)
 }
 
 func generateFooter(headerLen int, altCoin string, total decimal.Decimal) []string {
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/telebot.v3"
+
+	"github.com/erwanlbp/trading-bot/pkg/model"
+	"github.com/erwanlbp/trading-bot/pkg/telegram"
+	"github.com/erwanlbp/trading-bot/pkg/util"
+)
+
+const blackoutDateLayout = "2006-01-02T15:04"
+
+func (p *Handlers) ShowBlackouts(c telebot.Context) error {
+	loc, err := p.Conf.TradingWindows.Location()
+	if err != nil {
+		return c.Send("Invalid trading windows timezone: " + err.Error())
+	}
+
+	blackouts, err := p.Repository.GetUpcomingBlackouts(time.Now().UTC())
+	if err != nil {
+		return c.Send("Failed to get blackouts: " + err.Error())
+	}
+
+	parts := []string{p.TradingWindowStatus()}
+	if len(blackouts) == 0 {
+		parts = append(parts, "No upcoming blackout")
+	} else {
+		msg := util.ToASCIITable(blackouts, []string{"ID", "From", "To", "Reason"}, nil, func(b model.Blackout) []string {
+			return []string{strconv.Itoa(int(b.ID)), b.StartsAt.In(loc).Format(blackoutDateLayout), b.EndsAt.In(loc).Format(blackoutDateLayout), b.Reason}
+		})
+		parts = append(parts, "Upcoming blackouts ("+loc.String()+"):", telegram.FormatForMD(msg))
+	}
+	parts = append(parts, "Add one with `/add_blackout 2024-06-12T19:30 2h FOMC`, remove with `/remove_blackout ID`")
+
+	return c.Send(strings.Join(parts, "\n"))
+}
+
+// /add_blackout START DURATION [REASON], START in the trading windows timezone
+func (p *Handlers) AddBlackout(c telebot.Context) error {
+	args := c.Args()
+	if len(args) < 2 {
+		return c.Send("Usage: /add_blackout 2024-06-12T19:30 2h REASON")
+	}
+
+	loc, err := p.Conf.TradingWindows.Location()
+	if err != nil {
+		return c.Send("Invalid trading windows timezone: " + err.Error())
+	}
+	start, err := time.ParseInLocation(blackoutDateLayout, args[0], loc)
+	if err != nil {
+		return c.Send(fmt.Sprintf("couldn't parse start (%s), expected %s: %s", args[0], blackoutDateLayout, err.Error()))
+	}
+	duration, err := time.ParseDuration(args[1])
+	if err != nil || duration <= 0 {
+		return c.Send(fmt.Sprintf("couldn't parse duration (%s), expected something like 2h", args[1]))
+	}
+
+	blackout := model.Blackout{
+		StartsAt: start.UTC(),
+		EndsAt:   start.Add(duration).UTC(),
+		Reason:   strings.Join(args[2:], " "),
+	}
+	if err := p.Repository.AddBlackout(&blackout); err != nil {
+		return c.Send("Failed to save blackout: " + err.Error())
+	}
+
+	return c.Send(fmt.Sprintf("Added blackout #%d, no jump from %s to %s (%s)", blackout.ID, start.Format(blackoutDateLayout), start.Add(duration).Format(blackoutDateLayout), loc))
+}
+
+func (p *Handlers) RemoveBlackout(c telebot.Context) error {
+	if len(c.Args()) != 1 {
+		return c.Send("Usage: /remove_blackout ID, see /blackouts")
+	}
+	id, err := strconv.ParseUint(c.Args()[0], 10, 64)
+	if err != nil {
+		return c.Send("Invalid blackout ID " + c.Args()[0])
+	}
+
+	deleted, err := p.Repository.DeleteBlackout(uint(id))
+	if err != nil {
+		return c.Send("Failed to remove blackout: " + err.Error())
+	}
+	if !deleted {
+		return c.Send(fmt.Sprintf("No blackout #%d", id))
+	}
+	return c.Send(fmt.Sprintf("Removed blackout #%d", id))
+}
+
+// Whether jumps are allowed now, or when they will be
+func (p *Handlers) TradingWindowStatus() string {
+	now := time.Now().UTC()
+	next, err := p.Repository.NextTradingTime(now)
+	if err != nil {
+		return "🚫 Jumps not allowed: " + err.Error()
+	}
+	if !next.After(now) {
+		return "✅ Jumps are allowed now"
+	}
+	loc, _ := p.Conf.TradingWindows.Location()
+	return fmt.Sprintf("🚫 Jumps not allowed until %s (%s)", next.In(loc).Format(time.DateTime), loc)
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gopkg.in/telebot.v3"
+)
+
+func (p *Handlers) ShowCircuitBreaker(c telebot.Context) error {
+	cb, err := p.Repository.GetCircuitBreaker()
+	if err != nil {
+		return c.Send("Failed to get circuit breaker state: " + err.Error())
+	}
+
+	conf := p.Conf.CircuitBreaker
+	parts := []string{
+		fmt.Sprintf("Circuit breaker is %s (daily loss %s %%, drawdown %s %%, failed jumps %d)", enabledStr(conf.Enabled), conf.MaxDailyLoss, conf.MaxDrawdown, conf.MaxFailedJumps),
+		fmt.Sprintf("Failed jumps in a row: %d", cb.ConsecutiveFailedJumps),
+	}
+	if cb.Tripped {
+		parts = append(parts, fmt.Sprintf("🚨 Tripped on %s: %s", cb.TrippedOn.Format(time.DateTime), cb.Reason), "Send /resume to continue jumping")
+	} else {
+		parts = append(parts, "✅ Not tripped")
+	}
+
+	return c.Send(strings.Join(parts, "\n"))
+}
+
+// Resume jumps halted by the circuit breaker, the limits are then measured from now
+func (p *Handlers) ResumeCircuitBreaker(c telebot.Context) error {
+	cb, err := p.Repository.GetCircuitBreaker()
+	if err != nil {
+		return c.Send("Failed to get circuit breaker state: " + err.Error())
+	}
+	if !cb.Tripped {
+		return c.Send("Circuit breaker is not tripped, nothing to resume", mainMenu)
+	}
+
+	if err := p.Repository.ResumeCircuitBreaker(time.Now().UTC()); err != nil {
+		return c.Send("Failed to resume circuit breaker: " + err.Error())
+	}
+
+	parts := []string{fmt.Sprintf("Circuit breaker resumed, limits are now measured from now (tripped because %s)", cb.Reason)}
+	if paused, err := p.Repository.IsPaused(); err == nil && paused {
+		parts = append(parts, "Jumps are still paused, send /unpause to continue")
+	} else {
+		parts = append(parts, "Jumps will start again on next tick")
+	}
+	return c.Send(strings.Join(parts, "\n"), mainMenu)
+}
@@ -18,14 +18,17 @@ import (
 )
 
 func (p *Handlers) ExportDB(c telebot.Context) error {
-	copiedFilepath, err := p.GlobalConf.ExportDBFile()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	content, fileName, err := p.GlobalConf.ExportDBFile(ctx)
 	if err != nil {
 		return c.Send("Failed to export DB: " + err.Error())
 	}
 
 	file := &telebot.Document{
-		File:     telebot.FromReader(copiedFilepath),
-		FileName: fmt.Sprintf("db_%s.db", time.Now().Format(time.DateOnly)),
+		File:     telebot.FromReader(content),
+		FileName: fileName,
 	}
 
 	return c.Send(file, mainMenu)
@@ -40,7 +43,7 @@ func (p *Handlers) ShowConfigFile(c telebot.Context) error {
 }
 
 func (p *Handlers) ShowLiveConfig(c telebot.Context) error {
-	return c.Send(PrepareConfContentForMessage(*p.Conf), mainMenu)
+	return c.Send(p.withPauseStatus(PrepareConfContentForMessage(*p.Conf)), mainMenu)
 }
 
 func (p *Handlers) ReloadConfigFile(c telebot.Context) error {
@@ -65,19 +68,65 @@ func (p *Handlers) ListCoins(c telebot.Context) error {
 		return coins[i].Coin < coins[j].Coin
 	})
 
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	stats := p.BinanceClient.GetTickerStats(ctx)
+
+	jumps, err := p.Repository.GetJumps()
+	if err != nil {
+		return c.Send("Failed to get jumps: " + err.Error())
+	}
+	holdings := util.GroupByProperty(model.Holdings(jumps, p.Conf.Bridge), func(h model.Holding) string { return h.Coin })
+	lastHoldings, err := p.GlobalConf.GetLastHoldings(p.Conf.LosingCoins.Holdings)
+	if err != nil {
+		return c.Send("Failed to get last holdings: " + err.Error())
+	}
+	suspensions, err := p.Repository.GetCoinSuspensions()
+	if err != nil {
+		return c.Send("Failed to get coin suspensions: " + err.Error())
+	}
+
+	var illiquid, suspended []string
 	coinsStr := util.ToASCIITable(coins,
-		[]string{"Coin", "Enabled", "Since"},
+		[]string{"Coin", "Enabled", "Since", "Vol 24h", "Spread", "Liquid", "Holds", "Avg"},
 		nil,
 		func(c model.Coin) []string {
 			var e int
 			if c.Enabled {
 				e = 1
 			}
-			return []string{c.Coin, strconv.Itoa(e), c.EnabledOn.Format("2006-01-02")}
+			volume, spread, liquid := "?", "?", "?"
+			if s, ok := stats[util.Symbol(c.Coin, p.Conf.Bridge)]; ok {
+				volume = util.CountSI(s.QuoteVolume.IntPart())
+				spread = s.Spread().StringFixed(3) + "%"
+				liquid = "1"
+				if reason := s.CheckLiquidity(p.Conf.Liquidity.MinQuoteVolume, p.Conf.Liquidity.MaxSpread); reason != "" {
+					liquid = "0"
+					illiquid = append(illiquid, fmt.Sprintf("%s: %s", c.Coin, reason))
+				}
+			}
+			// Same holdings as the ones checked by the losing coin guard
+			last := model.LastHoldings(lastHoldings[c.Coin], suspensions[c.Coin].Until, p.Conf.LosingCoins.Holdings)
+			avg := "-"
+			if len(last) > 0 {
+				avg = model.AvgResult(last).StringFixed(2) + "%"
+			}
+			if s := suspensions[c.Coin]; s.Active {
+				suspended = append(suspended, fmt.Sprintf("%s until %s: %s", c.Coin, s.Until.Format(time.DateTime), s.Reason))
+			}
+			return []string{c.Coin, strconv.Itoa(e), c.EnabledOn.Format("2006-01-02"), volume, spread, liquid, strconv.Itoa(len(holdings[c.Coin])), avg}
 		})
 
 	var messageParts []string = []string{}
 	messageParts = append(messageParts, "```", coinsStr, "```")
+	if len(illiquid) > 0 {
+		messageParts = append(messageParts, "Not jumped to, not liquid enough:")
+		messageParts = append(messageParts, illiquid...)
+	}
+	if len(suspended) > 0 {
+		messageParts = append(messageParts, "Disabled for losing value:")
+		messageParts = append(messageParts, suspended...)
+	}
 
 	return c.Send(strings.Join(messageParts, "\n"), telebot.RemoveKeyboard, configurationMenu)
 }
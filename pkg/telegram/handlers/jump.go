@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -36,7 +37,7 @@ func (p *Handlers) NextJump(c telebot.Context) error {
 		return []string{diff.LogSymbol(), diff.Diff.Mul(decimal.NewFromInt(100)).StringFixed(1) + " %"}
 	})
 
-	parts := []string{ts, telegram.FormatForMD(msg)}
+	parts := []string{ts, telegram.FormatForMD(msg), p.TradingWindowStatus()}
 
 	return c.Send(strings.Join(parts, "\n"))
 }
@@ -72,9 +73,37 @@ func (p *Handlers) LastTenJumps(c telebot.Context) error {
 	}
 
 	msg := util.ToASCIITable(jumps, []string{"Date", "Pair"}, nil, func(jump model.Jump) []string {
+		pair := util.LogSymbol(jump.FromCoin, jump.ToCoin)
+		if jump.Manual {
+			pair += " ✋"
+		}
 		return []string{
 			jump.Timestamp.Format(time.DateOnly) + "\n" + jump.Timestamp.Format(time.TimeOnly),
-			util.LogSymbol(jump.FromCoin, jump.ToCoin),
+			pair,
+		}
+	})
+
+	return c.Send(telegram.FormatForMD(msg))
+}
+
+func (p *Handlers) LastProposals(c telebot.Context) error {
+	if !p.Conf.Approval.Enabled {
+		return c.Send("Semi-automatic mode is not enabled, jumps are done without approval")
+	}
+
+	proposals, err := p.Repository.GetJumpProposals(repository.OrderBy("created_on desc"), repository.Limit(10))
+	if err != nil {
+		return c.Send("Error while getting last proposals, please retry")
+	}
+	if len(proposals) < 1 {
+		return c.Send("No jump proposal found in DB")
+	}
+
+	msg := util.ToASCIITable(proposals, []string{"Date", "Path", "Status"}, nil, func(proposal model.JumpProposal) []string {
+		return []string{
+			proposal.CreatedOn.Format(time.DateOnly) + "\n" + proposal.CreatedOn.Format(time.TimeOnly),
+			strings.Join(proposal.Coins(), "/"),
+			string(proposal.Status),
 		}
 	})
 
@@ -91,8 +120,8 @@ func (p *Handlers) EditJump(c telebot.Context) error {
 	jump := p.Conf.Jump
 
 	messageParts = append(messageParts, fmt.Sprintf(
-		"`/edit_jump when:%s decrease:%s after:%s min:%s`",
-		jump.WhenGain, jump.DecreaseBy, jump.After, jump.Min,
+		"`/edit_jump when:%s decrease:%s after:%s min:%s hops:%d`",
+		jump.WhenGain, jump.DecreaseBy, jump.After, jump.Min, jump.MaxHops,
 	))
 
 	return c.Send(strings.Join(messageParts, "\n"), telebot.RemoveKeyboard, configurationMenu)
@@ -134,6 +163,12 @@ func (p *Handlers) ValidateJumpEdit(c telebot.Context) error {
 				return c.Send(fmt.Sprintf("couldn't parse 'min' (%s) argument: %s", arg, err.Error()))
 			}
 			jumpConf.Min = val
+		case "hops":
+			val, err := strconv.Atoi(arg)
+			if err != nil || val < 1 || val > configfile.MaxJumpHops {
+				return c.Send(fmt.Sprintf("couldn't parse 'hops' (%s) argument, must be between 1 and %d", arg, configfile.MaxJumpHops))
+			}
+			jumpConf.MaxHops = val
 		default:
 			continue
 		}
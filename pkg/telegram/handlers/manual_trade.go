@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"gopkg.in/telebot.v3"
+)
+
+const (
+	manualConfirmUnique = "manual_trade_confirm"
+	manualCancelUnique  = "manual_trade_cancel"
+
+	manualJumpTo = "jump_to"
+	manualExit   = "exit"
+	manualEnter  = "enter"
+
+	// A confirmation button clicked later than this is ignored
+	manualConfirmationTTL = 2 * time.Minute
+)
+
+func (p *Handlers) ManualJumpTo(c telebot.Context) error {
+	if len(c.Args()) != 1 {
+		return c.Send("You must give one coin: /jump_to COIN")
+	}
+	coin := strings.ToUpper(c.Args()[0])
+	return p.askManualTradeConfirmation(c, manualJumpTo, coin, fmt.Sprintf("✋ Jump from current coin to %s ?", coin))
+}
+
+func (p *Handlers) ManualExitToBridge(c telebot.Context) error {
+	return p.askManualTradeConfirmation(c, manualExit, p.Conf.Bridge, fmt.Sprintf("✋ Sell current coin to %s ? The bot will stay on the bridge until /enter", p.Conf.Bridge))
+}
+
+func (p *Handlers) ManualEnter(c telebot.Context) error {
+	if len(c.Args()) != 1 {
+		return c.Send("You must give one coin: /enter COIN")
+	}
+	coin := strings.ToUpper(c.Args()[0])
+	return p.askManualTradeConfirmation(c, manualEnter, coin, fmt.Sprintf("✋ Buy %s with %s ?", coin, p.Conf.Bridge))
+}
+
+func (p *Handlers) askManualTradeConfirmation(c telebot.Context, action, coin, question string) error {
+	data := strings.Join([]string{action, coin, strconv.FormatInt(time.Now().Unix(), 10)}, ":")
+
+	markup := &telebot.ReplyMarkup{}
+	markup.Inline(markup.Row(
+		markup.Data("✅ Confirm", manualConfirmUnique, data),
+		markup.Data("❌ Cancel", manualCancelUnique, data),
+	))
+
+	return c.Send(question, markup)
+}
+
+// The callback is answered right away and the buttons removed, so a second tap can't start a second trade. Trades run on the bot context, shutdown cancels them
+func (p *Handlers) ConfirmManualTrade(ctx context.Context, c telebot.Context) error {
+	parts := strings.Split(c.Data(), ":")
+	if len(parts) != 3 {
+		return c.Edit("Invalid confirmation")
+	}
+	action, coin := parts[0], parts[1]
+	askedOn, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil || time.Since(time.Unix(askedOn, 0)) > manualConfirmationTTL {
+		return c.Edit(c.Message().Text + "\n\n⌛ Confirmation expired, send the command again")
+	}
+
+	if err := c.Respond(&telebot.CallbackResponse{Text: "On it"}); err != nil {
+		p.Logger.Warn("Failed to answer manual trade callback", zap.Error(err))
+	}
+
+	// Editing without the buttons removes them
+	text := c.Message().Text
+	if err := c.Edit(text + "\n\n⏳ Executing…"); err != nil {
+		p.Logger.Warn("Failed to edit manual trade message", zap.Error(err))
+	}
+
+	// Trades can take up to the trade timeout
+	ctx, cancel := context.WithTimeout(ctx, p.Conf.TradeTimeout+time.Minute)
+	defer cancel()
+
+	switch action {
+	case manualJumpTo:
+		err = p.GlobalConf.ManualJumpTo(ctx, coin)
+	case manualExit:
+		err = p.GlobalConf.ManualExitToBridge(ctx)
+	case manualEnter:
+		err = p.GlobalConf.ManualEnter(ctx, coin)
+	default:
+		err = fmt.Errorf("unknown action %s", action)
+	}
+	if err != nil {
+		return c.Edit(text + "\n\n❌ Failed: " + err.Error())
+	}
+
+	return c.Edit(text + "\n\n✅ Done")
+}
+
+func (p *Handlers) CancelManualTrade(c telebot.Context) error {
+	return c.Edit(c.Message().Text + "\n\nCanceled")
+}
@@ -18,12 +18,16 @@ var (
 	btnBestJump      = mainMenu.Text("⭐️ Best jump")
 	btnConfiguration = mainMenu.Text("⚙️ Configuration")
 	btnChart         = mainMenu.Text("📊 Chart")
+	btnPauseResume   = mainMenu.Text("⏯️ Pause/Resume")
 
 	// Balance menu
 	balanceMenu       = &telebot.ReplyMarkup{ResizeKeyboard: true}
 	btnBalanceUSDT    = mainMenu.Text("💲 USDT")
 	btnBalanceBTC     = mainMenu.Text("₿ BTC")
 	btnBalanceHistory = mainMenu.Text("📊 History")
+	btnBalanceShadow  = mainMenu.Text("👻 Shadow")
+	btnStrategies     = mainMenu.Text("🧪 Strategies")
+	btnProfits        = mainMenu.Text("💰 Profits")
 
 	// Chart menu
 	chartMenu   = &telebot.ReplyMarkup{ResizeKeyboard: true}
@@ -45,17 +49,35 @@ var availableCommands = []string{
 	"/help",
 	"/balances ALT",
 	"/balances_charts",
+	"/shadow",
+	"/strategies",
+	"/strategy NAME",
+	"/profits",
+	"/release_reserve 100",
 	"/last_jumps",
+	"/proposals",
 	"/next_jump",
 	"/best_jump",
 	"/new_chart",
 	"/chart COIN1/COIN2 3",
 	"/chart COIN1,COIN2,COIN3 3",
+	"/jump_to COIN",
+	"/exit_to_bridge",
+	"/enter COIN",
+	"/blackouts",
+	"/add_blackout 2024-06-12T19:30 2h REASON",
+	"/remove_blackout ID",
+	"/pause 2h REASON",
+	"/unpause",
+	"/circuit_breaker",
+	"/resume",
 	"/export_db",
+	"/backfill COIN1,COIN2 7 1m",
 	"/reload_config",
 	"/live_config",
 	"/config_file",
 	"/list_coins",
+	"/universe",
 	"/edit_coins COIN1,COIN2,COIN3",
 	"/edit_jump when:3 decrease:0.1 after:1h min:0.1",
 }
@@ -74,14 +96,40 @@ func (p *Handlers) InitMenu(ctx context.Context) {
 	p.TelegramClient.CreateHandler(&btnBalanceBTC, p.ShowBtcBalances)
 	p.TelegramClient.CreateHandler("/balances_charts", p.ShowBalancesChart)
 	p.TelegramClient.CreateHandler(&btnBalanceHistory, p.ShowBalancesChart)
+	p.TelegramClient.CreateHandler("/shadow", p.ShowShadow)
+	p.TelegramClient.CreateHandler(&btnBalanceShadow, p.ShowShadow)
+	p.TelegramClient.CreateHandler("/strategies", p.ShowStrategies)
+	p.TelegramClient.CreateHandler(&btnStrategies, p.ShowStrategies)
+	p.TelegramClient.CreateHandler("/strategy", p.ShowStrategy)
+	p.TelegramClient.CreateHandler("/profits", p.ShowProfits)
+	p.TelegramClient.CreateHandler(&btnProfits, p.ShowProfits)
+	p.TelegramClient.CreateHandler("/release_reserve", p.ReleaseReserve)
 
 	p.TelegramClient.CreateHandler("/last_jumps", p.LastTenJumps)
 	p.TelegramClient.CreateHandler(&btnLast10Jumps, p.LastTenJumps)
+	p.TelegramClient.CreateHandler("/proposals", p.LastProposals)
 	p.TelegramClient.CreateHandler("/next_jump", p.NextJump)
 	p.TelegramClient.CreateHandler(&btnNextJump, p.NextJump)
 	p.TelegramClient.CreateHandler("/best_jump", p.BestJump)
 	p.TelegramClient.CreateHandler(&btnBestJump, p.BestJump)
 
+	p.TelegramClient.CreateHandler("/jump_to", p.ManualJumpTo)
+	p.TelegramClient.CreateHandler("/exit_to_bridge", p.ManualExitToBridge)
+	p.TelegramClient.CreateHandler("/enter", p.ManualEnter)
+	p.TelegramClient.CreateHandler(&telebot.Btn{Unique: manualConfirmUnique}, func(c telebot.Context) error {
+		return p.ConfirmManualTrade(ctx, c)
+	})
+	p.TelegramClient.CreateHandler(&telebot.Btn{Unique: manualCancelUnique}, p.CancelManualTrade)
+
+	p.TelegramClient.CreateHandler("/blackouts", p.ShowBlackouts)
+	p.TelegramClient.CreateHandler("/add_blackout", p.AddBlackout)
+	p.TelegramClient.CreateHandler("/remove_blackout", p.RemoveBlackout)
+	p.TelegramClient.CreateHandler("/pause", p.Pause)
+	p.TelegramClient.CreateHandler("/unpause", p.Unpause)
+	p.TelegramClient.CreateHandler(&btnPauseResume, p.TogglePause)
+	p.TelegramClient.CreateHandler("/circuit_breaker", p.ShowCircuitBreaker)
+	p.TelegramClient.CreateHandler("/resume", p.ResumeCircuitBreaker)
+
 	p.TelegramClient.CreateHandler(&btnChart, p.ChartMenu)
 	p.TelegramClient.CreateHandler(&btnNewChart, p.NewChart)
 	p.TelegramClient.CreateHandler("/new_chart", p.ValidateNewChart)
@@ -93,6 +141,7 @@ func (p *Handlers) InitMenu(ctx context.Context) {
 	})
 	p.TelegramClient.CreateHandler("/export_db", p.ExportDB)
 	p.TelegramClient.CreateHandler(&btnExportDB, p.ExportDB)
+	p.TelegramClient.CreateHandler("/backfill", p.Backfill)
 	p.TelegramClient.CreateHandler("/reload_config", p.ReloadConfigFile)
 	p.TelegramClient.CreateHandler(&btnReloadConfig, p.ReloadConfigFile)
 	p.TelegramClient.CreateHandler("/live_config", p.ShowLiveConfig)
@@ -101,6 +150,7 @@ func (p *Handlers) InitMenu(ctx context.Context) {
 	p.TelegramClient.CreateHandler(&btnShowConfigFile, p.ShowConfigFile)
 	p.TelegramClient.CreateHandler("/list_coins", p.ListCoins)
 	p.TelegramClient.CreateHandler(&btnListCoins, p.ListCoins)
+	p.TelegramClient.CreateHandler("/universe", p.ShowUniverse)
 	p.TelegramClient.CreateHandler(&btnEditCoins, p.EditCoins)
 	p.TelegramClient.CreateHandler("/edit_coins", p.ValidateCoinEdit)
 	p.TelegramClient.CreateHandler(&btnEditJump, p.EditJump)
@@ -117,10 +167,11 @@ func (p *Handlers) InitMenu(ctx context.Context) {
 	mainMenu.Reply(
 		mainMenu.Row(btnBalance, btnLast10Jumps),
 		mainMenu.Row(btnNextJump, btnBestJump),
-		mainMenu.Row(btnChart, btnConfiguration),
+		mainMenu.Row(btnChart, btnPauseResume, btnConfiguration),
 	)
 	balanceMenu.Reply(
 		balanceMenu.Row(btnBalanceUSDT, btnBalanceBTC, btnBalanceHistory),
+		balanceMenu.Row(btnBalanceShadow, btnStrategies, btnProfits),
 		balanceMenu.Row(btnBackToMainMenu),
 	)
 	configurationMenu.Reply(
@@ -135,7 +186,7 @@ func (p *Handlers) InitMenu(ctx context.Context) {
 	)
 
 	p.TelegramClient.CreateHandler("/menu", func(c telebot.Context) error {
-		return c.Send("What do you want to do ?", mainMenu)
+		return c.Send(p.withPauseStatus("What do you want to do ?"), mainMenu)
 	})
 }
 
@@ -150,5 +201,12 @@ func (p *Handlers) Help(c telebot.Context) error {
 }
 
 func (p *Handlers) BackToMainMenu(c telebot.Context) error {
-	return c.Send("Back to menu", mainMenu)
+	return c.Send(p.withPauseStatus("Back to menu"), mainMenu)
+}
+
+func (p *Handlers) withPauseStatus(msg string) string {
+	if status := p.PauseStatus(); status != "" {
+		return status + "\n" + msg
+	}
+	return msg
 }
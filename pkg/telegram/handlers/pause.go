@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gopkg.in/telebot.v3"
+)
+
+// /pause [DURATION] [REASON], paused until /unpause if no duration is given
+func (p *Handlers) Pause(c telebot.Context) error {
+	args := c.Args()
+
+	var until time.Time
+	now := time.Now().UTC()
+	if len(args) > 0 {
+		if d, err := time.ParseDuration(args[0]); err == nil {
+			if d <= 0 {
+				return c.Send("Pause duration must be positive")
+			}
+			until = now.Add(d)
+			args = args[1:]
+		}
+	}
+
+	if err := p.Repository.Pause(strings.Join(args, " "), until, now); err != nil {
+		return c.Send("Failed to pause: " + err.Error())
+	}
+
+	pause, err := p.Repository.GetPause()
+	if err != nil {
+		return c.Send("Paused, but failed to get pause state: " + err.Error())
+	}
+
+	return c.Send("⏸️ Paused, "+pause.Description()+"\nPrices, charts and balances are still saved", mainMenu)
+}
+
+// Lift the pause. A tripped circuit breaker stays tripped, it's resumed on its own with /resume
+func (p *Handlers) Unpause(c telebot.Context) error {
+	cb, err := p.Repository.GetCircuitBreaker()
+	if err != nil {
+		return c.Send("Failed to get circuit breaker state: " + err.Error())
+	}
+	halted := ""
+	if cb.Tripped {
+		halted = fmt.Sprintf("\n🚨 The circuit breaker is still tripped (%s), jumps stay halted until /resume", cb.Reason)
+	}
+
+	pause, err := p.Repository.GetPause()
+	if err != nil {
+		return c.Send("Failed to get pause state: " + err.Error())
+	}
+	if !pause.IsActive(time.Now().UTC()) {
+		return c.Send("Not paused, nothing to lift"+halted, mainMenu)
+	}
+
+	if err := p.Repository.Unpause(); err != nil {
+		return c.Send("Failed to unpause: " + err.Error())
+	}
+
+	if halted != "" {
+		return c.Send("▶️ Not paused anymore"+halted, mainMenu)
+	}
+	return c.Send("▶️ Not paused anymore, jumps will start again on next tick", mainMenu)
+}
+
+func (p *Handlers) TogglePause(c telebot.Context) error {
+	paused, err := p.Repository.IsPaused()
+	if err != nil {
+		return c.Send("Failed to get pause state: " + err.Error())
+	}
+	if paused {
+		return p.Unpause(c)
+	}
+	return p.Pause(c)
+}
+
+// One line about the pause, empty if not paused
+func (p *Handlers) PauseStatus() string {
+	pause, err := p.Repository.GetPause()
+	if err != nil {
+		return "Failed to get pause state: " + err.Error()
+	}
+	if !pause.IsActive(time.Now().UTC()) {
+		return ""
+	}
+	return "⏸️ " + pause.Description()
+}
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gopkg.in/telebot.v3"
+
+	"github.com/erwanlbp/trading-bot/pkg/model"
+	"github.com/erwanlbp/trading-bot/pkg/repository"
+	"github.com/erwanlbp/trading-bot/pkg/telegram"
+	"github.com/erwanlbp/trading-bot/pkg/util"
+)
+
+// Realized profits come from take profits, unrealized is the current position value compared to its reference
+func (p *Handlers) ShowProfits(c telebot.Context) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	realized, err := p.Repository.GetRealizedProfit()
+	if err != nil {
+		return c.Send("Failed to get realized profit: "+err.Error(), balanceMenu)
+	}
+	reserve, err := p.Repository.GetBridgeReserve()
+	if err != nil {
+		return c.Send("Failed to get banked amount: "+err.Error(), balanceMenu)
+	}
+	reference, err := p.Repository.GetPositionReference()
+	if err != nil {
+		return c.Send("Failed to get position reference: "+err.Error(), balanceMenu)
+	}
+
+	parts := []string{
+		fmt.Sprintf("Take profit is %s (gain %s %%, sell %s %%)", enabledStr(p.Conf.TakeProfit.Enabled), p.Conf.TakeProfit.Gain, p.Conf.TakeProfit.SellRatio),
+		fmt.Sprintf("Realized: %s %s", realized.StringFixed(2), p.Conf.Bridge),
+		fmt.Sprintf("Banked in bridge: %s %s", reserve.StringFixed(2), p.Conf.Bridge),
+	}
+	if reserve.IsPositive() {
+		parts = append(parts, "Send /release_reserve to spend it again")
+	}
+
+	if unrealized, err := p.unrealizedProfit(ctx, reference); err != nil {
+		parts = append(parts, "Unrealized: "+err.Error())
+	} else {
+		parts = append(parts, fmt.Sprintf("Unrealized: %s %s (since %s)", unrealized.StringFixed(2), p.Conf.Bridge, reference.Timestamp.Format(time.DateTime)))
+	}
+
+	takeProfits, err := p.Repository.GetTakeProfits(repository.OrderBy("timestamp desc"), repository.Limit(5))
+	if err != nil {
+		return c.Send("Failed to get last take profits: "+err.Error(), balanceMenu)
+	}
+	if len(takeProfits) > 0 {
+		msg := util.ToASCIITable(takeProfits, []string{"Date", "Coin", "Profit"}, nil, func(tp model.TakeProfit) []string {
+			return []string{tp.Timestamp.Format(time.DateOnly), tp.Coin, tp.RealizedProfit.StringFixed(2)}
+		})
+		parts = append(parts, telegram.FormatForMD(msg))
+	}
+
+	return c.Send(strings.Join(parts, "\n"), balanceMenu)
+}
+
+// Make the banked bridge spendable again, all of it or the given amount
+func (p *Handlers) ReleaseReserve(c telebot.Context) error {
+	reserve, err := p.Repository.GetBridgeReserve()
+	if err != nil {
+		return c.Send("Failed to get banked amount: " + err.Error())
+	}
+	if !reserve.IsPositive() {
+		return c.Send("Nothing is banked in bridge", balanceMenu)
+	}
+
+	amount := reserve
+	if len(c.Args()) > 0 {
+		amount, err = decimal.NewFromString(c.Args()[0])
+		if err != nil || !amount.IsPositive() {
+			return c.Send("Amount must be a positive number, like /release_reserve 100")
+		}
+		amount = decimal.Min(amount, reserve)
+	}
+
+	if err := p.Repository.ReleaseBridgeReserve(amount, model.ReserveReleasedManually, time.Now().UTC()); err != nil {
+		return c.Send("Failed to release banked amount: " + err.Error())
+	}
+
+	return c.Send(fmt.Sprintf("Released %s %s, %s %s still banked", amount.StringFixed(2), p.Conf.Bridge, reserve.Sub(amount).StringFixed(2), p.Conf.Bridge), balanceMenu)
+}
+
+func (p *Handlers) unrealizedProfit(ctx context.Context, reference model.PositionReference) (decimal.Decimal, error) {
+	if reference.Timestamp.IsZero() {
+		return decimal.Zero, fmt.Errorf("no reference yet")
+	}
+
+	cc, _, err := p.Repository.GetCurrentCoin()
+	if err != nil {
+		return decimal.Zero, err
+	}
+	if cc.Coin == "" || cc.Coin == p.Conf.Bridge {
+		return decimal.Zero, fmt.Errorf("no position")
+	}
+
+	balances, err := p.BinanceClient.GetBalance(ctx, cc.Coin)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	prices, err := p.Repository.GetCoinsLastPrice(p.Conf.Bridge)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	for _, price := range prices {
+		if price.Coin == cc.Coin {
+			return balances[cc.Coin].Mul(price.SellPrice()).Sub(reference.Value), nil
+		}
+	}
+	return decimal.Zero, fmt.Errorf("no price found for %s", cc.Coin)
+}
+
+func enabledStr(enabled bool) string {
+	if enabled {
+		return "enabled"
+	}
+	return "disabled"
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/telebot.v3"
+
+	"github.com/erwanlbp/trading-bot/pkg/config/configfile"
+	"github.com/erwanlbp/trading-bot/pkg/service"
+	"github.com/erwanlbp/trading-bot/pkg/util"
+)
+
+func (p *Handlers) ShowUniverse(c telebot.Context) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	proposal, err := p.GlobalConf.ProposeUniverse(ctx)
+	if err != nil {
+		return c.Send("Failed to rank coins: " + err.Error())
+	}
+
+	conf := p.Conf.Universe
+	ranking := proposal.Ranking
+	if len(ranking) > conf.Size+5 {
+		ranking = ranking[:conf.Size+5]
+	}
+
+	inList := util.AsSet(p.Conf.Coins, util.Identity[string]())
+	rank := make(map[string]int)
+	for i, r := range ranking {
+		rank[r.Coin] = i + 1
+	}
+	table := util.ToASCIITable(ranking,
+		[]string{"#", "Coin", "Score", "Listed"},
+		nil,
+		func(r service.RankedCoin) []string {
+			var listed int
+			if inList[r.Coin] {
+				listed = 1
+			}
+			score := util.CountSI(r.Score.IntPart())
+			if conf.RankBy == configfile.RankByVolatility {
+				score = r.Score.StringFixed(1) + "%"
+			}
+			return []string{strconv.Itoa(rank[r.Coin]), r.Coin, score, strconv.Itoa(listed)}
+		})
+
+	mode := "proposed"
+	if conf.Apply {
+		mode = "applied"
+	}
+	parts := []string{
+		fmt.Sprintf("Universe manager is %s, ranking by %s, %d coins, changes are %s", enabledStr(conf.Enabled), conf.RankBy, conf.Size, mode),
+		"```", table, "```",
+	}
+	if proposal.HasChanges() {
+		parts = append(parts, proposal.Describe()...)
+	} else {
+		parts = append(parts, "✅ Coin list is up to date")
+	}
+
+	return c.Send(strings.Join(parts, "\n"))
+}
@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gopkg.in/telebot.v3"
+
+	"github.com/erwanlbp/trading-bot/pkg/config/configfile"
+	"github.com/erwanlbp/trading-bot/pkg/constant"
+	"github.com/erwanlbp/trading-bot/pkg/model"
+	"github.com/erwanlbp/trading-bot/pkg/repository"
+	"github.com/erwanlbp/trading-bot/pkg/telegram"
+	"github.com/erwanlbp/trading-bot/pkg/util"
+)
+
+func (p *Handlers) ShowShadow(c telebot.Context) error {
+	if !p.Conf.Shadow.Enabled {
+		return c.Send("Shadow mode is not enabled", balanceMenu)
+	}
+	return p.showVirtualStrategy(c, configfile.ShadowStrategyName)
+}
+
+func (p *Handlers) ShowStrategy(c telebot.Context) error {
+	if len(c.Args()) != 1 {
+		return c.Send("You must give one strategy name, see /strategies", balanceMenu)
+	}
+	return p.showVirtualStrategy(c, c.Args()[0])
+}
+
+func (p *Handlers) showVirtualStrategy(c telebot.Context, strategy string) error {
+	summary, err := p.VirtualPortfolioSummary(strategy)
+	if err != nil {
+		return c.Send("Failed to get virtual portfolio: "+err.Error(), balanceMenu)
+	}
+
+	parts := []string{telegram.EscapeMD(summary)}
+
+	jumps, err := p.Repository.GetVirtualJumps(repository.Strategy(strategy), repository.OrderBy("timestamp desc"), repository.Limit(10))
+	if err != nil {
+		return c.Send("Failed to get virtual jumps: "+err.Error(), balanceMenu)
+	}
+	if len(jumps) > 0 {
+		msg := util.ToASCIITable(jumps, []string{"Date", "Pair"}, nil, func(jump model.VirtualJump) []string {
+			return []string{
+				jump.Timestamp.Format(time.DateOnly) + "\n" + jump.Timestamp.Format(time.TimeOnly),
+				util.LogSymbol(jump.FromCoin, jump.ToCoin),
+			}
+		})
+		parts = append(parts, "Last virtual jumps:", telegram.FormatForMD(msg))
+	}
+
+	return c.Send(strings.Join(parts, "\n"), balanceMenu)
+}
+
+// Rank the virtual strategies by performance since their start, with the live performance on the same period
+func (p *Handlers) ShowStrategies(c telebot.Context) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	strategies := p.Conf.VirtualStrategies()
+	if len(strategies) == 0 {
+		return c.Send("No virtual strategy enabled", balanceMenu)
+	}
+
+	liveValue, err := p.BinanceClient.GetBalanceValue(ctx, []string{constant.USDT})
+	if err != nil {
+		return c.Send("Failed to get live balance value: "+err.Error(), balanceMenu)
+	}
+
+	type rankingLine struct {
+		Name     string
+		Value    decimal.Decimal
+		Perf     decimal.Decimal
+		LivePerf *decimal.Decimal
+		Jumps    int
+	}
+	var lines []rankingLine
+	for _, strategy := range strategies {
+		portfolio, exists, err := p.Repository.GetVirtualPortfolio(strategy.Name)
+		if err != nil {
+			return c.Send("Failed to get virtual portfolio: "+err.Error(), balanceMenu)
+		}
+		if !exists {
+			continue
+		}
+		value, err := p.VirtualPortfolioValue(portfolio)
+		if err != nil {
+			return c.Send(fmt.Sprintf("Failed to get value of strategy %s: %s", strategy.Name, err.Error()), balanceMenu)
+		}
+		jumps, err := p.Repository.GetVirtualJumps(repository.Strategy(strategy.Name))
+		if err != nil {
+			return c.Send("Failed to get virtual jumps: "+err.Error(), balanceMenu)
+		}
+
+		line := rankingLine{Name: strategy.Name, Value: value, Perf: perfPct(portfolio.StartBalance, value), Jumps: len(jumps)}
+
+		// Live performance on the same period, from the balance saved when the strategy started
+		if history, err := p.Repository.GetFirstBalanceHistoryAfter(portfolio.StartedOn); err == nil && !history.UsdtBalance.IsZero() {
+			line.LivePerf = util.WrapPtr(perfPct(history.UsdtBalance, liveValue[constant.USDT]))
+		}
+
+		lines = append(lines, line)
+	}
+	if len(lines) == 0 {
+		return c.Send("No virtual strategy started yet", balanceMenu)
+	}
+
+	sort.SliceStable(lines, func(i, j int) bool {
+		return lines[i].Perf.GreaterThan(lines[j].Perf)
+	})
+
+	msg := util.ToASCIITable(lines, []string{"Strategy", p.Conf.Bridge, "Perf", "Live", "Jumps"}, nil, func(line rankingLine) []string {
+		livePerf := "?"
+		if line.LivePerf != nil {
+			livePerf = line.LivePerf.StringFixed(2) + " %"
+		}
+		return []string{line.Name, line.Value.StringFixed(2), line.Perf.StringFixed(2) + " %", livePerf, strconv.Itoa(line.Jumps)}
+	})
+
+	parts := []string{
+		"Strategies performance since their start, live is on the same period (in USDT)",
+		telegram.FormatForMD(msg),
+		"Details with `/strategy NAME`",
+	}
+
+	return c.Send(strings.Join(parts, "\n"), balanceMenu)
+}
+
+func perfPct(from, to decimal.Decimal) decimal.Decimal {
+	if from.IsZero() {
+		return decimal.Zero
+	}
+	return to.Div(from).Sub(decimal.NewFromInt(1)).Mul(decimal.NewFromInt(100))
+}
+
+// One line description of the virtual portfolio, valued in bridge with last prices
+func (p *Handlers) VirtualPortfolioSummary(strategy string) (string, error) {
+	portfolio, exists, err := p.Repository.GetVirtualPortfolio(strategy)
+	if err != nil {
+		return "", err
+	}
+	if !exists {
+		return fmt.Sprintf("👻 %s: not started yet", strategy), nil
+	}
+
+	value, err := p.VirtualPortfolioValue(portfolio)
+	if err != nil {
+		return "", err
+	}
+
+	perf := perfPct(portfolio.StartBalance, value)
+
+	return fmt.Sprintf("👻 %s: %s %s ≈ %s %s (%s %% since %s)",
+		strategy,
+		portfolio.Quantity.StringFixed(4), portfolio.Coin,
+		value.StringFixed(2), p.Conf.Bridge,
+		perf.StringFixed(2), portfolio.StartedOn.Format(time.DateOnly),
+	), nil
+}
+
+func (p *Handlers) VirtualPortfolioValue(portfolio model.VirtualPortfolio) (decimal.Decimal, error) {
+	if portfolio.Coin == p.Conf.Bridge {
+		return portfolio.Quantity, nil
+	}
+
+	prices, err := p.Repository.GetCoinsLastPrice(p.Conf.Bridge)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	for _, price := range prices {
+		if price.Coin == portfolio.Coin {
+			return portfolio.Quantity.Mul(price.Price), nil
+		}
+	}
+	return decimal.Zero, fmt.Errorf("no price found for %s", portfolio.Coin)
+}
@@ -3,3 +3,10 @@ package util
 import "time"
 
 const Day time.Duration = 24 * time.Hour
+
+func MaxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}